@@ -1,12 +1,15 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/jhoffmann/bookmark-manager/internal/app"
 	"github.com/jhoffmann/bookmark-manager/internal/models"
+	"github.com/jhoffmann/bookmark-manager/internal/service"
 	"github.com/jhoffmann/bookmark-manager/internal/tui/styles"
 	"github.com/spf13/cobra"
 )
@@ -14,83 +17,189 @@ import (
 // addCmd represents the add command
 var addCmd = &cobra.Command{
 	Use:   "add [category]",
-	Short: "Add the current directory as a bookmark",
-	Long: `Add the current directory as a bookmark with an optional category.
+	Short: "Add the current directory (or other folders) as a bookmark",
+	Long: `Add the current directory, or the folders given with --path, as bookmarks
+with an optional category and any number of additional tags. The category is
+the bookmark's primary tag. Without one, new bookmarks get the default_category
+setting of the config file (see config), if any.
+
+--alias gives a single bookmark a short unique name, so it can be referred to
+as @alias by resolve, rm and set-category and the shell integration.
+
+Folders that are already bookmarked are left alone unless --update is given,
+in which case their category is replaced (when one is given), the tags are
+added and the alias is set (an empty --alias removes it).
+
+With --scan, a directory tree is walked instead and every directory containing
+a project marker (by default .git, go.mod or package.json) is bookmarked. The
+category can be derived per directory with --category-template, a Go template
+with the fields .Name (directory name), .Parent (parent directory name),
+.Root (scan root name) and .Rel (path relative to the scan root).
 
 Examples:
   bookmark-manager add
   bookmark-manager add work
   bookmark-manager add personal
-  bookmark-manager add "my-project"`,
-	Args: cobra.MaximumNArgs(1),
-	Run:  runAdd,
+  bookmark-manager add "my-project"
+  bookmark-manager add work --tag go --tag api
+  bookmark-manager add work --alias api
+  bookmark-manager add --path ~/src/api --path ~/src/web --category work
+  bookmark-manager add --update --category archive
+  bookmark-manager add --scan ~/src --depth 2 --dry-run
+  bookmark-manager add --scan ~/src --match go.mod --category-template '{{.Parent}}'`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: positional(completeTags),
+	Run:               runAdd,
 }
 
 func runAdd(cmd *cobra.Command, args []string) {
+	tags, _ := cmd.Flags().GetStringArray("tag")
+	categoryFlag, _ := cmd.Flags().GetString("category")
+	scanRoot, _ := cmd.Flags().GetString("scan")
+
+	if scanRoot != "" {
+		if cmd.Flags().Changed("alias") {
+			fmt.Fprintf(os.Stderr, "%s --alias cannot be used with --scan\n",
+				styles.ErrorMessage.Render("✗"))
+			os.Exit(1)
+		}
+		runAddScan(cmd, args, scanRoot)
+		return
+	}
+
+	paths, _ := cmd.Flags().GetStringArray("path")
+	update, _ := cmd.Flags().GetBool("update")
+	aliasFlag, _ := cmd.Flags().GetString("alias")
+
+	// A nil alias leaves existing aliases alone
+	var alias *string
+	if cmd.Flags().Changed("alias") {
+		aliasFlag = models.NormalizeAlias(aliasFlag)
+		if aliasFlag != "" {
+			if err := models.ValidateAlias(aliasFlag); err != nil {
+				fmt.Fprintf(os.Stderr, "%s %v\n", styles.ErrorMessage.Render("✗"), err)
+				os.Exit(1)
+			}
+		}
+		if len(paths) > 1 {
+			fmt.Fprintf(os.Stderr, "%s --alias can only be used with a single folder\n",
+				styles.ErrorMessage.Render("✗"))
+			os.Exit(1)
+		}
+		alias = &aliasFlag
+	}
+
+	// Default to the current working directory
+	if len(paths) == 0 {
+		currentDir, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("%s Failed to get current directory: %v\n",
+				styles.ErrorMessage.Render("✗"), err)
+			os.Exit(1)
+		}
+		paths = []string{currentDir}
+	}
+
+	// Determine category
+	category := models.CategoryType(categoryFlag)
+	if len(args) > 0 && args[0] != "" {
+		category = models.CategoryType(args[0])
+	}
+
 	// Initialize app (loads config, database, and service)
 	appInstance := app.InitializeOrExit()
 	defer appInstance.Close()
 
-	// Get current working directory
-	currentDir, err := os.Getwd()
-	if err != nil {
-		fmt.Printf("%s Failed to get current directory: %v\n",
-			styles.ErrorMessage.Render("✗"), err)
+	// New bookmarks without a category get the configured default one, if any
+	defaultCategory := models.CategoryType(appInstance.Config.GetDefaultCategory())
+
+	failed := false
+	for _, path := range paths {
+		if err := addPath(appInstance.Service, path, category, defaultCategory, tags, alias, update); err != nil {
+			fmt.Printf("%s %v\n", styles.ErrorMessage.Render("✗"), err)
+			failed = true
+		}
+	}
+
+	if failed {
 		os.Exit(1)
 	}
+}
 
+// addPath bookmarks a single folder, or updates its bookmark when update is set.
+// The alias is only applied when it is not nil, and defaultCategory only to a
+// new bookmark without a category.
+func addPath(bookmarks *service.Bookmarks, path string, category, defaultCategory models.CategoryType, tags []string, alias *string, update bool) error {
 	// Get absolute path to ensure consistency
-	absPath, err := filepath.Abs(currentDir)
+	absPath, err := filepath.Abs(path)
 	if err != nil {
-		fmt.Printf("%s Failed to get absolute path: %v\n",
-			styles.ErrorMessage.Render("✗"), err)
-		os.Exit(1)
+		return fmt.Errorf("failed to get absolute path: %w", err)
 	}
 
-	// Determine category
-	var category models.CategoryType
-	if len(args) > 0 && args[0] != "" {
-		category = models.CategoryType(args[0])
+	if status := service.NewFolders().CheckFolder(absPath); status != service.FolderOK {
+		return fmt.Errorf("cannot bookmark %s: %s", absPath, status)
 	}
-	// If no category provided, it will remain empty
 
 	// Check if bookmark already exists
-	existingBookmarks, err := appInstance.Service.SearchByFolder(absPath)
-	if err != nil {
-		fmt.Printf("%s Failed to check for existing bookmarks: %v\n",
-			styles.ErrorMessage.Render("✗"), err)
-		os.Exit(1)
+	existing, err := bookmarks.GetByFolder(absPath)
+	if err != nil && !errors.Is(err, service.ErrNotFound) {
+		return fmt.Errorf("failed to check for existing bookmarks: %w", err)
 	}
 
-	// Check for exact match
-	for _, existing := range existingBookmarks {
-		if existing.Folder == absPath {
-			fmt.Printf("%s Bookmark already exists: %s [%s]\n",
-				styles.WarningMessage.Render("!"),
-				existing.Folder,
-				existing.Category)
-			return
+	if existing != nil {
+		if !update {
+			fmt.Printf("%s Bookmark already exists: %s\n",
+				styles.WarningMessage.Render("!"), describeBookmark(existing))
+			return nil
+		}
+
+		if category != "" {
+			existing.Category = category
+		}
+		if alias != nil {
+			existing.Alias = *alias
+		}
+		existing.SetTagNames(append(existing.TagNames(), tags...))
+		if err := bookmarks.Save(existing); err != nil {
+			return fmt.Errorf("failed to update bookmark: %w", err)
 		}
+
+		fmt.Printf("%s Updated bookmark: %s\n",
+			styles.SuccessMessage.Render("✓"), describeBookmark(existing))
+		return nil
 	}
 
 	// Create new bookmark
+	if category == "" {
+		category = defaultCategory
+	}
 	newBookmark := &models.Bookmark{
 		Folder:   absPath,
 		Category: category,
 	}
+	if alias != nil {
+		newBookmark.Alias = *alias
+	}
+	newBookmark.SetTagNames(tags)
 
 	// Save bookmark
-	if err := appInstance.Service.Save(newBookmark); err != nil {
-		fmt.Printf("%s Failed to save bookmark: %v\n",
-			styles.ErrorMessage.Render("✗"), err)
-		os.Exit(1)
+	if err := bookmarks.Save(newBookmark); err != nil {
+		return fmt.Errorf("failed to save bookmark: %w", err)
 	}
 
 	// Success message
-	fmt.Printf("%s Added bookmark: %s [%s]\n",
-		styles.SuccessMessage.Render("✓"),
-		absPath,
-		category)
+	fmt.Printf("%s Added bookmark: %s\n",
+		styles.SuccessMessage.Render("✓"), describeBookmark(newBookmark))
+	return nil
+}
+
+// describeBookmark formats a bookmark's folder, alias and tags for messages
+func describeBookmark(b *models.Bookmark) string {
+	description := b.Folder
+	if b.Alias != "" {
+		description += " " + models.AliasPrefix + b.Alias
+	}
+	return description + " [" + strings.Join(b.TagNames(), ", ") + "]"
 }
 
 // GetAddCmd returns the add command
@@ -99,6 +208,19 @@ func GetAddCmd() *cobra.Command {
 }
 
 func init() {
-	// Add flags if needed in the future
-	// addCmd.Flags().BoolVarP(&force, "force", "f", false, "Force add even if bookmark exists")
+	addCmd.Flags().StringArrayP("tag", "t", nil, "Tag to attach to the bookmark (repeatable)")
+	addCmd.Flags().StringP("category", "c", "", "Category of the bookmark (same as the positional argument)")
+	addCmd.Flags().StringArrayP("path", "p", nil, "Folder to bookmark instead of the current directory (repeatable)")
+	addCmd.Flags().StringP("alias", "a", "", "Unique short name to refer to the bookmark as @alias")
+	addCmd.Flags().BoolP("update", "u", false, "Update the category and tags of folders that are already bookmarked")
+	addCmd.Flags().String("scan", "", "Bookmark every project directory found under this root")
+	addCmd.Flags().Int("depth", 3, "Maximum directory depth to scan")
+	addCmd.Flags().StringArray("match", service.DefaultProjectMarkers, "File or directory marking a project root when scanning (repeatable, globs allowed)")
+	addCmd.Flags().String("category-template", "", "Go template deriving the category of scanned directories, e.g. '{{.Parent}}'")
+	addCmd.Flags().Bool("dry-run", false, "Show what a scan would bookmark without saving anything")
+
+	_ = addCmd.RegisterFlagCompletionFunc("tag", completeTags)
+	_ = addCmd.RegisterFlagCompletionFunc("category", completeTags)
+	_ = addCmd.MarkFlagDirname("path")
+	_ = addCmd.MarkFlagDirname("scan")
 }
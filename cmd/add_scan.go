@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/jhoffmann/bookmark-manager/internal/app"
+	"github.com/jhoffmann/bookmark-manager/internal/models"
+	"github.com/jhoffmann/bookmark-manager/internal/service"
+	"github.com/jhoffmann/bookmark-manager/internal/tui/styles"
+	"github.com/spf13/cobra"
+)
+
+// scanTemplateData holds the fields available to --category-template
+type scanTemplateData struct {
+	Name   string
+	Parent string
+	Root   string
+	Rel    string
+}
+
+// runAddScan bookmarks every project directory found under root
+func runAddScan(cmd *cobra.Command, args []string, root string) {
+	tags, _ := cmd.Flags().GetStringArray("tag")
+	categoryFlag, _ := cmd.Flags().GetString("category")
+	depth, _ := cmd.Flags().GetInt("depth")
+	markers, _ := cmd.Flags().GetStringArray("match")
+	categoryTemplate, _ := cmd.Flags().GetString("category-template")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	category := categoryFlag
+	if len(args) > 0 && args[0] != "" {
+		category = args[0]
+	}
+	if category != "" && categoryTemplate != "" {
+		fmt.Fprintf(os.Stderr, "%s A category and --category-template cannot be combined\n",
+			styles.ErrorMessage.Render("✗"))
+		os.Exit(1)
+	}
+
+	var tmpl *template.Template
+	if categoryTemplate != "" {
+		var err error
+		tmpl, err = template.New("category").Option("missingkey=error").Parse(categoryTemplate)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s Invalid category template: %v\n",
+				styles.ErrorMessage.Render("✗"), err)
+			os.Exit(1)
+		}
+	}
+
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s Failed to get absolute path: %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+
+	found, err := service.NewFolders().ScanForProjects(absRoot, depth, markers)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+
+	if len(found) == 0 {
+		fmt.Printf("%s No project directories found under %s\n",
+			styles.WarningMessage.Render("!"), absRoot)
+		return
+	}
+
+	cfg, err := app.LoadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+
+	if category == "" && tmpl == nil {
+		category = cfg.GetDefaultCategory()
+	}
+
+	// A dry run only reads the bookmarks, neither creating nor migrating the
+	// database, and finds none before there is a database
+	var appInstance *app.App
+	if dryRun {
+		appInstance, err = app.InitializeReadOnly(cfg)
+		if errors.Is(err, fs.ErrNotExist) {
+			err = nil
+		}
+	} else {
+		appInstance, err = app.InitializeWithConfig(cfg)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+
+	bookmarked := make(map[string]bool)
+	if appInstance != nil {
+		defer appInstance.Close()
+
+		existing, err := appInstance.Service.List(0, 0)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s Failed to check for existing bookmarks: %v\n",
+				styles.ErrorMessage.Render("✗"), err)
+			os.Exit(1)
+		}
+		for _, b := range existing {
+			bookmarked[b.Folder] = true
+		}
+	}
+
+	var bookmarks []*models.Bookmark
+	for _, dir := range found {
+		if bookmarked[dir] {
+			fmt.Printf("%s Bookmark already exists: %s\n",
+				styles.WarningMessage.Render("!"), dir)
+			continue
+		}
+
+		dirCategory := category
+		if tmpl != nil {
+			dirCategory, err = renderScanCategory(tmpl, absRoot, dir)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s Failed to render category for %s: %v\n",
+					styles.ErrorMessage.Render("✗"), dir, err)
+				os.Exit(1)
+			}
+		}
+
+		b := &models.Bookmark{
+			Folder:   dir,
+			Category: models.CategoryType(dirCategory),
+		}
+		b.SetTagNames(tags)
+		bookmarks = append(bookmarks, b)
+	}
+
+	if dryRun {
+		for _, b := range bookmarks {
+			// The category joins the tags on save; show it the same way
+			names := models.NormalizeTagNames(append([]string{string(b.Category)}, b.TagNames()...))
+			fmt.Printf("%s Would add bookmark: %s [%s]\n",
+				styles.SuccessMessage.Render("•"),
+				b.Folder,
+				strings.Join(names, ", "))
+		}
+		fmt.Printf("%d bookmark(s) would be added\n", len(bookmarks))
+		return
+	}
+
+	result, err := appInstance.Service.Import(bookmarks)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+
+	for _, b := range bookmarks {
+		if b.ID != 0 {
+			fmt.Printf("%s Added bookmark: %s [%s]\n",
+				styles.SuccessMessage.Render("✓"),
+				b.Folder,
+				strings.Join(b.TagNames(), ", "))
+		}
+	}
+	fmt.Printf("%s Added %d bookmark(s) from %s\n",
+		styles.SuccessMessage.Render("✓"), result.Created, absRoot)
+}
+
+// renderScanCategory evaluates the category template for a scanned directory
+func renderScanCategory(tmpl *template.Template, root, dir string) (string, error) {
+	rel, err := filepath.Rel(root, dir)
+	if err != nil {
+		return "", err
+	}
+
+	data := scanTemplateData{
+		Name:   filepath.Base(dir),
+		Parent: filepath.Base(filepath.Dir(dir)),
+		Root:   filepath.Base(root),
+		Rel:    filepath.ToSlash(rel),
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jhoffmann/bookmark-manager/internal/app"
+	"github.com/jhoffmann/bookmark-manager/internal/models"
+	"github.com/jhoffmann/bookmark-manager/internal/service"
+	"github.com/jhoffmann/bookmark-manager/internal/tui/styles"
+	"github.com/spf13/cobra"
+)
+
+// categoryCmd represents the category command
+var categoryCmd = &cobra.Command{
+	Use:   "category",
+	Short: "List, rename, merge and remove categories",
+	Long: `Categories are tags: a bookmark is in every category it has a tag for, and
+its category (shown first) is the primary one. Categories exist as long as
+bookmarks have them. These commands change the tag on every bookmark outside
+the trash that has it, all at once or not at all, and the primary category
+follows. Other tags are left alone.
+
+Examples:
+  bookmark-manager category list
+  bookmark-manager category rename wrk work
+  bookmark-manager category merge job projects work
+  bookmark-manager category rm archive
+  bookmark-manager category rm old --trash`,
+	Args: cobra.NoArgs,
+	Run:  runCategoryList,
+}
+
+// categoryListCmd represents the category list command
+var categoryListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List categories with their number of bookmarks",
+	Long: `List the categories with their number of bookmarks outside the trash. A
+bookmark with several tags counts in each of them.`,
+	Args: cobra.NoArgs,
+	Run:  runCategoryList,
+}
+
+// categoryRenameCmd represents the category rename command
+var categoryRenameCmd = &cobra.Command{
+	Use:   "rename <category> <new-name>",
+	Short: "Rename a category",
+	Long: `Rename a category. The new name must not be in use yet; use merge to
+combine two categories.`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: positional(completeTags),
+	Run:               runCategoryRename,
+}
+
+// categoryMergeCmd represents the category merge command
+var categoryMergeCmd = &cobra.Command{
+	Use:               "merge <category>... <target>",
+	Short:             "Move the bookmarks of categories into another one",
+	Args:              cobra.MinimumNArgs(2),
+	ValidArgsFunction: completeTags,
+	Run:               runCategoryMerge,
+}
+
+// categoryRmCmd represents the category rm command
+var categoryRmCmd = &cobra.Command{
+	Use:   "rm <category>...",
+	Short: "Remove categories from their bookmarks",
+	Long: `Remove categories from their bookmarks, which keep their other tags; those
+whose primary category it was are left without one. With --trash the
+bookmarks are moved to the trash instead. Nothing changes when one of the
+categories has no bookmarks.`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeTags,
+	Run:               runCategoryRm,
+}
+
+func runCategoryList(cmd *cobra.Command, args []string) {
+	// Initialize app (loads config, database, and service)
+	appInstance := app.InitializeOrExit()
+	defer appInstance.Close()
+
+	counts, err := appInstance.Service.CategoryCounts(nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+
+	if len(counts) == 0 {
+		fmt.Printf("%s No bookmarks yet\n", styles.SuccessMessage.Render("✓"))
+		return
+	}
+
+	for _, c := range counts {
+		name := string(c.Category)
+		if name == "" {
+			name = "(none)"
+		}
+		fmt.Printf("%5d  %s\n", c.Count, name)
+	}
+}
+
+func runCategoryRename(cmd *cobra.Command, args []string) {
+	// Initialize app (loads config, database, and service)
+	appInstance := app.InitializeOrExit()
+	defer appInstance.Close()
+
+	changed, err := appInstance.Service.RenameCategory(args[0], args[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("%s Renamed category %s to %s (%d bookmark(s))\n",
+		styles.SuccessMessage.Render("✓"), args[0], args[1], len(changed))
+}
+
+func runCategoryMerge(cmd *cobra.Command, args []string) {
+	// Initialize app (loads config, database, and service)
+	appInstance := app.InitializeOrExit()
+	defer appInstance.Close()
+
+	sources, target := args[:len(args)-1], args[len(args)-1]
+	changed, err := appInstance.Service.MergeCategories(sources, target)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("%s Merged %d bookmark(s) into category %s\n",
+		styles.SuccessMessage.Render("✓"), len(changed), target)
+}
+
+func runCategoryRm(cmd *cobra.Command, args []string) {
+	trash, _ := cmd.Flags().GetBool("trash")
+
+	// Initialize app (loads config, database, and service)
+	appInstance := app.InitializeOrExit()
+	defer appInstance.Close()
+
+	var count int
+	var err error
+	if trash {
+		count, err = trashCategories(appInstance, args)
+	} else {
+		var changed map[uint]service.Labels
+		changed, err = appInstance.Service.RemoveCategories(args...)
+		count = len(changed)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+
+	if trash {
+		fmt.Printf("%s Moved %d bookmark(s) of %s to the trash\n",
+			styles.SuccessMessage.Render("✓"), count, describeCategories(args))
+	} else {
+		fmt.Printf("%s Removed %s from %d bookmark(s)\n",
+			styles.SuccessMessage.Render("✓"), describeCategories(args), count)
+	}
+}
+
+// trashCategories moves the bookmarks of the categories to the trash, returning
+// how many there were. Nothing is moved when a category has no bookmarks.
+func trashCategories(appInstance *app.App, categories []string) (int, error) {
+	var ids []uint
+	seen := make(map[uint]bool)
+	for _, category := range categories {
+		bookmarks, err := appInstance.Service.SearchByCategory(models.CategoryType(category))
+		if err != nil {
+			return 0, err
+		}
+		if len(bookmarks) == 0 {
+			return 0, fmt.Errorf("category %q not found", category)
+		}
+
+		for _, b := range bookmarks {
+			if !seen[b.ID] {
+				seen[b.ID] = true
+				ids = append(ids, b.ID)
+			}
+		}
+	}
+
+	if err := appInstance.Service.DeleteByIDs(ids); err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+// describeCategories returns "category <name>" or "categories <a>, <b>"
+func describeCategories(categories []string) string {
+	if len(categories) == 1 {
+		return "category " + categories[0]
+	}
+	return "categories " + strings.Join(categories, ", ")
+}
+
+// GetCategoryCmd returns the category command
+func GetCategoryCmd() *cobra.Command {
+	return categoryCmd
+}
+
+func init() {
+	categoryRmCmd.Flags().Bool("trash", false, "Move the bookmarks to the trash instead of keeping them without a category")
+	categoryCmd.AddCommand(categoryListCmd, categoryRenameCmd, categoryMergeCmd, categoryRmCmd)
+}
@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jhoffmann/bookmark-manager/internal/app"
+	"github.com/jhoffmann/bookmark-manager/internal/config"
+	"github.com/jhoffmann/bookmark-manager/internal/models"
+	"github.com/jhoffmann/bookmark-manager/internal/service"
+	"github.com/spf13/cobra"
+)
+
+// completionLimit caps the candidates of each kind offered to the shell, so
+// completion stays fast on large databases
+const completionLimit = 100
+
+// queryFields are the field prefixes of the query language
+var queryFields = []string{"cat:", "tag:", "path:", "name:", "alias:", "added:", "visited:"}
+
+// queryAges are example values for the added: and visited: fields
+var queryAges = []string{"<1d", "<7d", "<30d", ">30d", ">1y"}
+
+// completeFunc is the signature of cobra argument and flag completion functions
+type completeFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// withBookmarks adapts a completer needing the bookmark service to cobra. The
+// database given by the config flags is opened quietly and read-only, as
+// completion runs on every keystroke: it is neither created nor migrated.
+// Completion is silently skipped when it cannot be opened, since anything
+// printed would end up as a candidate.
+func withBookmarks(complete func(bookmarks *service.Bookmarks, args []string, toComplete string) ([]string, cobra.ShellCompDirective)) completeFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		applyConfigFlags(cmd)
+		cfg, err := config.Load()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		cfg.LogLevel = "silent"
+
+		appInstance, err := app.InitializeReadOnly(cfg)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		defer appInstance.Close()
+
+		return complete(appInstance.Service, args, toComplete)
+	}
+}
+
+// positional completes the nth positional argument with one completer per
+// position, offering nothing past the last one
+func positional(completers ...completeFunc) completeFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) >= len(completers) || completers[len(args)] == nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completers[len(args)](cmd, args, toComplete)
+	}
+}
+
+// fixedCompletions offers a fixed list of values
+func fixedCompletions(values ...string) completeFunc {
+	return cobra.FixedCompletions(values, cobra.ShellCompDirectiveNoFileComp)
+}
+
+// completeTags offers the tags in use, which are the categories
+var completeTags = withBookmarks(func(bookmarks *service.Bookmarks, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	tags, err := bookmarks.TagNamesWithPrefix(toComplete, completionLimit)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return tags, cobra.ShellCompDirectiveNoFileComp
+})
+
+// completeBookmarkRefs offers bookmark IDs and @aliases described by their
+// folders, falling back to file completion for paths
+var completeBookmarkRefs = withBookmarks(func(bookmarks *service.Bookmarks, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if strings.HasPrefix(toComplete, models.AliasPrefix) {
+		return aliasCompletions(bookmarks, toComplete)
+	}
+	if _, err := strconv.ParseUint(toComplete, 10, 0); toComplete != "" && err != nil {
+		// Neither an ID nor an alias, so a path
+		return nil, cobra.ShellCompDirectiveFilterDirs
+	}
+
+	found, err := bookmarks.FindByIDPrefix(toComplete, false, completionLimit)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	completions := idCompletions(found)
+
+	if toComplete == "" {
+		aliases, _ := aliasCompletions(bookmarks, "")
+		completions = append(aliases, completions...)
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+})
+
+// completeTrashedIDs offers the IDs of bookmarks in the trash
+var completeTrashedIDs = withBookmarks(func(bookmarks *service.Bookmarks, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	found, err := bookmarks.FindByIDPrefix(toComplete, true, completionLimit)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return idCompletions(found), cobra.ShellCompDirectiveNoFileComp
+})
+
+// completeAliasRef offers @aliases and, once an alias is complete, the
+// directories below its folder
+var completeAliasRef = withBookmarks(func(bookmarks *service.Bookmarks, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	ref := toComplete
+	if !strings.HasPrefix(ref, models.AliasPrefix) {
+		ref = models.AliasPrefix + ref
+	}
+
+	alias, subPath, _ := models.ParseAliasRef(ref)
+	if !strings.ContainsAny(ref, `/\`) {
+		return aliasCompletions(bookmarks, alias)
+	}
+
+	bookmark, err := bookmarks.GetByAlias(alias)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// Complete the last element of the path below the folder
+	dir, base := filepath.Split(filepath.FromSlash(subPath))
+	entries, err := os.ReadDir(filepath.Join(bookmark.Folder, dir))
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var completions []string
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), base) {
+			continue
+		}
+		// Hidden directories are only offered when asked for
+		if strings.HasPrefix(entry.Name(), ".") && !strings.HasPrefix(base, ".") {
+			continue
+		}
+		completions = append(completions, models.AliasPrefix+alias+"/"+filepath.ToSlash(filepath.Join(dir, entry.Name()))+"/")
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+})
+
+// completeFilterTerms offers folder names and tags, the words a fuzzy filter
+// or jump query is likely to be made of
+var completeFilterTerms = withBookmarks(func(bookmarks *service.Bookmarks, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	names, err := bookmarks.FolderNames(toComplete, completionLimit)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	tags, err := bookmarks.TagNamesWithPrefix(toComplete, completionLimit)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	completions := names
+	for _, tag := range tags {
+		completions = append(completions, tag+"\ttag")
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+})
+
+// completeQuery completes the last term of a --query value: field names,
+// tags after cat: and tag:, aliases after alias: and @, and example ages
+// after added: and visited:
+var completeQuery = withBookmarks(func(bookmarks *service.Bookmarks, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	// Only the last term is completed; the rest is kept as typed
+	start := strings.LastIndexAny(toComplete, " \t(") + 1
+	for start < len(toComplete) && toComplete[start] == '-' {
+		start++
+	}
+	before, term := toComplete[:start], toComplete[start:]
+
+	var values []string
+	directive := cobra.ShellCompDirectiveNoFileComp
+
+	field, value, hasField := strings.Cut(term, ":")
+	switch {
+	case strings.HasPrefix(term, models.AliasPrefix):
+		found, err := bookmarks.FindByAliasPrefix(term, completionLimit)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		for _, b := range found {
+			values = append(values, models.AliasPrefix+b.Alias)
+		}
+
+	case !hasField:
+		for _, f := range queryFields {
+			if strings.HasPrefix(f, strings.ToLower(term)) {
+				values = append(values, f)
+			}
+		}
+		directive |= cobra.ShellCompDirectiveNoSpace
+
+	case field == "cat" || field == "category" || field == "tag":
+		tags, err := bookmarks.TagNamesWithPrefix(value, completionLimit)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		for _, tag := range tags {
+			values = append(values, field+":"+tag)
+		}
+
+	case field == "alias":
+		found, err := bookmarks.FindByAliasPrefix(value, completionLimit)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		for _, b := range found {
+			values = append(values, field+":"+b.Alias)
+		}
+
+	case field == "added" || field == "visited":
+		for _, age := range queryAges {
+			if strings.HasPrefix(age, value) {
+				values = append(values, field+":"+age)
+			}
+		}
+	}
+
+	completions := make([]string, len(values))
+	for i, v := range values {
+		completions[i] = before + v
+	}
+	return completions, directive
+})
+
+// aliasCompletions offers the aliases starting with prefix as @alias,
+// described by their folders
+func aliasCompletions(bookmarks *service.Bookmarks, prefix string) ([]string, cobra.ShellCompDirective) {
+	found, err := bookmarks.FindByAliasPrefix(prefix, completionLimit)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	completions := make([]string, len(found))
+	for i, b := range found {
+		completions[i] = models.AliasPrefix + b.Alias + "\t" + b.Folder
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// idCompletions offers bookmark IDs described by their folders
+func idCompletions(bookmarks []*models.Bookmark) []string {
+	completions := make([]string, len(bookmarks))
+	for i, b := range bookmarks {
+		completions[i] = strconv.FormatUint(uint64(b.ID), 10) + "\t" + b.Folder
+	}
+	return completions
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jhoffmann/bookmark-manager/internal/app"
+	"github.com/jhoffmann/bookmark-manager/internal/config"
+	"github.com/jhoffmann/bookmark-manager/internal/models"
+	"github.com/spf13/cobra"
+)
+
+func TestCompletions(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "bookmarks.db")
+	t.Setenv("BM_DATABASE", dbPath)
+
+	appInstance, err := app.InitializeWithConfig(&config.Config{DatabasePath: dbPath, LogLevel: "silent"})
+	if err != nil {
+		t.Fatalf("InitializeWithConfig() error = %v", err)
+	}
+	src := t.TempDir()
+	for _, b := range []*models.Bookmark{
+		{Folder: src, Category: "work", Alias: "src"},
+		{Folder: "/home/user/notes", Category: "personal"},
+	} {
+		if err := appInstance.Service.Save(b); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+	appInstance.Close()
+
+	tests := []struct {
+		name       string
+		complete   completeFunc
+		args       []string
+		toComplete string
+		want       []string
+	}{
+		{"categories", completeTags, nil, "w", []string{"work"}},
+		{"query fields", completeQuery, nil, "path:x -ca", []string{"path:x -cat:"}},
+		{"query tags", completeQuery, nil, "(cat:p", []string{"(cat:personal"}},
+		{"query aliases", completeQuery, nil, "@", []string{"@src"}},
+		{"aliases", completeBookmarkRefs, nil, "@s", []string{"@src\t" + src}},
+		{"past the last argument", positional(completeTags), []string{"work"}, "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, directive := tt.complete(&cobra.Command{}, tt.args, tt.toComplete)
+			if directive&cobra.ShellCompDirectiveError != 0 {
+				t.Fatalf("completion failed with directive %d", directive)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("completions for %q = %q, want %q", tt.toComplete, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,423 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jhoffmann/bookmark-manager/internal/config"
+	"github.com/jhoffmann/bookmark-manager/internal/tui/keys"
+	"github.com/jhoffmann/bookmark-manager/internal/tui/styles"
+	"github.com/spf13/cobra"
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show and edit the configuration file",
+	Long: `Show and edit the TOML configuration file.
+
+Settings are layered: command line flags override environment variables, which
+override the config file, which overrides the defaults. The file lives in the
+application's config directory (see config path) unless --config or BM_CONFIG
+point elsewhere.
+
+Settings:
+` + settingsHelp() + `
+Key bindings start from the keys.preset setting and are replaced per action
+in the [keys.list], [keys.confirm] and [keys.edit] tables, or with config set
+using keys.<scope>.<action> and a comma-separated list of keys. Actions:
+` + actionsHelp() + `
+Keys are named as the TUI reports them: a character, enter, tab, esc, space,
+up, pgdown, f1, ctrl+a or alt+x. A key bound to several actions of a scope is
+an error.
+
+Themes are defined in [themes.<name>] tables replacing colors of their base
+theme; see the template written by config edit.
+
+Examples:
+  bookmark-manager config show
+  bookmark-manager config set sort frecency
+  bookmark-manager config set opener 'code --new-window'
+  bookmark-manager config set keys.preset vim
+  bookmark-manager config set keys.list.delete x,D
+  bookmark-manager config set theme solarized
+  bookmark-manager config get database
+  bookmark-manager config edit`,
+	Args: cobra.NoArgs,
+	Run:  runConfigShow,
+}
+
+// configPathCmd represents the config path command
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the path of the config file",
+	Args:  cobra.NoArgs,
+	Run:   runConfigPath,
+}
+
+// configShowCmd represents the config show command
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the effective settings and where each one comes from",
+	Args:  cobra.NoArgs,
+	Run:   runConfigShow,
+}
+
+// configGetCmd represents the config get command
+var configGetCmd = &cobra.Command{
+	Use:               "get <key>",
+	Short:             "Print the effective value of a setting",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: positional(completeSettingKeys),
+	Run:               runConfigGet,
+}
+
+// configSetCmd represents the config set command
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a setting in the config file",
+	Long: `Set a setting in the config file, creating the file when needed. An empty
+value removes the setting. The file is rewritten, so comments are not kept.`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: positional(completeSettingKeys),
+	Run:               runConfigSet,
+}
+
+// configEditCmd represents the config edit command
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open the config file in $VISUAL or $EDITOR",
+	Long: `Open the config file in $VISUAL or $EDITOR, creating it with every setting
+documented and commented out when it does not exist yet. The file is validated
+once the editor exits.`,
+	Args: cobra.NoArgs,
+	Run:  runConfigEdit,
+}
+
+// configValidateCmd represents the config validate command
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [file]",
+	Short: "Check the config file and environment variables for errors",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   runConfigValidate,
+}
+
+func runConfigPath(cmd *cobra.Command, args []string) {
+	fmt.Println(configPathOrExit())
+}
+
+func runConfigShow(cmd *cobra.Command, args []string) {
+	cfg := loadConfigOrExit()
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SETTING\tVALUE\tORIGIN")
+	for _, s := range config.Settings() {
+		value, _ := config.Get(cfg, s.Key)
+		if value == "" {
+			value = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", s.Key, value, cfg.Origin(s.Key))
+	}
+
+	bindings := cfg.GetKeys()
+	for _, scope := range config.KeyScopes {
+		actions, _ := bindings.Scope(scope)
+		names := make([]string, 0, len(actions))
+		for action := range actions {
+			names = append(names, action)
+		}
+		slices.Sort(names)
+		for _, action := range names {
+			key := config.KeysPrefix + scope + "." + action
+			value, _ := config.Get(cfg, key)
+			if value == "" {
+				value = "-" // Unbound
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", key, value, cfg.Origin(key))
+		}
+	}
+	tw.Flush()
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) {
+	cfg := loadConfigOrExit()
+
+	value, err := config.Get(cfg, args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+	fmt.Println(value)
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) {
+	key, value := args[0], args[1]
+	path := configPathOrExit()
+
+	file, err := config.LoadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+	if file == nil {
+		file = &config.Config{}
+	}
+
+	if err := config.Set(file, key, value); err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+	if err := validateTUI(file); err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+	if err := config.Save(path, file); err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+
+	if value == "" {
+		fmt.Printf("%s Removed %s from %s\n", styles.SuccessMessage.Render("✓"), key, path)
+	} else {
+		fmt.Printf("%s Set %s to %s in %s\n", styles.SuccessMessage.Render("✓"), key, value, path)
+	}
+
+	// Tell why the change may not take effect
+	if cfg, err := config.Load(); err == nil {
+		if origin := cfg.Origin(key); origin != config.OriginFile && origin != config.OriginDefault {
+			fmt.Printf("%s %s is overridden by %s\n", styles.WarningMessage.Render("!"), key, origin)
+		}
+	}
+}
+
+func runConfigEdit(cmd *cobra.Command, args []string) {
+	path := configPathOrExit()
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := writeConfigTemplate(path); err != nil {
+			fmt.Fprintf(os.Stderr, "%s %v\n", styles.ErrorMessage.Render("✗"), err)
+			os.Exit(1)
+		}
+	}
+
+	editor := editorCommand()
+	editorCmd := exec.Command(editor[0], append(editor[1:], path)...)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+	if err := editorCmd.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "%s Failed to run %s: %v\n",
+			styles.ErrorMessage.Render("✗"), editor[0], err)
+		os.Exit(1)
+	}
+
+	file, err := config.LoadFile(path)
+	if err == nil && file != nil {
+		err = validateTUI(file)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", styles.ErrorMessage.Render("✗"), err)
+		fmt.Fprintf(os.Stderr, "Run 'bookmark-manager config edit' again to fix it.\n")
+		os.Exit(1)
+	}
+	fmt.Printf("%s %s is valid\n", styles.SuccessMessage.Render("✓"), path)
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) {
+	var path string
+	if len(args) > 0 {
+		path = args[0]
+		if _, err := os.Stat(path); err != nil {
+			fmt.Fprintf(os.Stderr, "%s %v\n", styles.ErrorMessage.Render("✗"), err)
+			os.Exit(1)
+		}
+		file, err := config.LoadFile(path)
+		if err == nil {
+			err = validateTUI(file)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s %v\n", styles.ErrorMessage.Render("✗"), err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s %s is valid\n", styles.SuccessMessage.Render("✓"), path)
+		return
+	}
+
+	// The file and the environment variables layered over it
+	path = configPathOrExit()
+	cfg, err := config.Load()
+	if err == nil {
+		err = validateTUI(cfg)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		fmt.Printf("%s No config file at %s; the environment is valid\n",
+			styles.SuccessMessage.Render("✓"), path)
+		return
+	}
+	fmt.Printf("%s %s is valid\n", styles.SuccessMessage.Render("✓"), path)
+}
+
+// completeSettingKeys offers the setting keys and the key binding actions
+func completeSettingKeys(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var completions []string
+	for _, s := range config.Settings() {
+		completions = append(completions, s.Key+"\t"+s.Usage)
+	}
+	for _, scope := range config.KeyScopes {
+		for _, a := range keys.ScopeActions(scope) {
+			completions = append(completions, config.KeysPrefix+scope+"."+a.Name+"\t"+a.Help)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// validateTUI checks the settings validated by the TUI packages: the key
+// bindings for unknown presets, actions and keys and for conflicts, and the
+// theme for unknown names and cycles
+func validateTUI(cfg *config.Config) error {
+	if _, err := keys.Load(cfg.GetKeys()); err != nil {
+		return fmt.Errorf("invalid key bindings:\n%w", err)
+	}
+	if _, err := styles.Load(cfg.GetTheme(), cfg.GetThemes()); err != nil {
+		return fmt.Errorf("invalid theme: %w", err)
+	}
+	// User themes that are not selected yet
+	names := make([]string, 0, len(cfg.GetThemes()))
+	for name := range cfg.GetThemes() {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if _, err := styles.Load(name, cfg.GetThemes()); err != nil {
+			return fmt.Errorf("invalid theme: %w", err)
+		}
+	}
+	return nil
+}
+
+// actionsHelp lists the key binding actions of each scope for help
+func actionsHelp() string {
+	var b strings.Builder
+	for _, scope := range config.KeyScopes {
+		// Wrapped to fit the rest of the help
+		actions := keys.ScopeActions(scope)
+		line := "  " + scope + ":"
+		for i, a := range actions {
+			if len(line)+len(a.Name) > 78 {
+				b.WriteString(line + "\n")
+				line = "   "
+			}
+			line += " " + a.Name
+			if i < len(actions)-1 {
+				line += ","
+			}
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// settingsHelp lists the settings and their environment variables for help
+func settingsHelp() string {
+	var b strings.Builder
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	for _, s := range config.Settings() {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\n", s.Key, s.Env, s.Usage)
+	}
+	tw.Flush()
+	return b.String()
+}
+
+// writeConfigTemplate creates a config file documenting every setting
+func writeConfigTemplate(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(config.Template()), 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// editorCommand returns the user's editor command, split into words
+func editorCommand() []string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.Fields(os.Getenv(name)); len(editor) > 0 {
+			return editor
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+	return []string{"vi"}
+}
+
+// configPathOrExit returns the config file in use and exits on error
+func configPathOrExit() string {
+	path, err := config.Path()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+	return path
+}
+
+// loadConfigOrExit loads the effective configuration and exits on error
+func loadConfigOrExit() *config.Config {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+	return cfg
+}
+
+// AddConfigFlags registers the persistent flags selecting the config file and
+// overriding settings on the root command, and --no-color. Overrides take
+// precedence over the environment and the config file.
+func AddConfigFlags(rootCmd *cobra.Command) {
+	rootCmd.PersistentFlags().String("config", "", "Config file to use instead of the default one (or BM_CONFIG)")
+	rootCmd.PersistentFlags().String("database", "", "Database file to use (overrides the database setting and BM_DATABASE)")
+	rootCmd.PersistentFlags().Bool("no-color", false, "Disable colors (same as NO_COLOR)")
+	_ = rootCmd.MarkPersistentFlagFilename("config", "toml")
+	_ = rootCmd.MarkPersistentFlagFilename("database", "db")
+
+	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		applyConfigFlags(cmd)
+	}
+}
+
+// applyConfigFlags applies the flags registered by AddConfigFlags. Commands
+// run them before starting; completion, which cobra runs without the
+// persistent hooks, applies them itself.
+func applyConfigFlags(cmd *cobra.Command) {
+	if noColor, _ := cmd.Flags().GetBool("no-color"); noColor {
+		styles.DisableColor()
+	}
+	if path, _ := cmd.Flags().GetString("config"); path != "" {
+		config.SetPath(path)
+	}
+	if cmd.Flags().Changed("database") {
+		database, _ := cmd.Flags().GetString("database")
+		config.Override("database", "database", database)
+	}
+}
+
+// GetConfigCmd returns the config command
+func GetConfigCmd() *cobra.Command {
+	return configCmd
+}
+
+func init() {
+	configCmd.AddCommand(configPathCmd, configShowCmd, configGetCmd, configSetCmd, configEditCmd, configValidateCmd)
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/jhoffmann/bookmark-manager/internal/config"
+	"github.com/jhoffmann/bookmark-manager/internal/database"
+	"github.com/jhoffmann/bookmark-manager/internal/tui/styles"
+	"github.com/spf13/cobra"
+)
+
+// dbCmd represents the db command
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Database maintenance commands",
+}
+
+// dbMigrateCmd represents the db migrate command
+var dbMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Apply pending database schema migrations",
+	Long: `Apply pending database schema migrations. Migrations are also applied
+automatically whenever the database is opened; this command makes the step
+explicit and lets you inspect the schema version with --status.
+
+Examples:
+  bookmark-manager db migrate
+  bookmark-manager db migrate --status`,
+	Args: cobra.NoArgs,
+	Run:  runDBMigrate,
+}
+
+func runDBMigrate(cmd *cobra.Command, args []string) {
+	showStatus, _ := cmd.Flags().GetBool("status")
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s Failed to load configuration: %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+
+	// Open without migrating so --status can report pending migrations; the
+	// status only reads the database, and never creates it
+	open := database.Open
+	if showStatus {
+		open = database.OpenReadOnly
+	}
+	db, err := open(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	if showStatus {
+		statuses, err := db.MigrationStatus()
+		if errors.Is(err, database.ErrNotVersioned) {
+			fmt.Printf("%s No schema_version table: the database has not been migrated by this\n  version yet; 'db migrate' records its schema and applies the migrations\n",
+				styles.WarningMessage.Render("•"))
+			return
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s Failed to read migration status: %v\n",
+				styles.ErrorMessage.Render("✗"), err)
+			os.Exit(1)
+		}
+
+		for _, s := range statuses {
+			if s.AppliedAt != nil {
+				fmt.Printf("%s %04d_%s (applied %s)\n",
+					styles.SuccessMessage.Render("✓"),
+					s.Version, s.Name,
+					s.AppliedAt.Format("2006-01-02 15:04:05"))
+			} else {
+				fmt.Printf("%s %04d_%s (pending)\n",
+					styles.WarningMessage.Render("•"),
+					s.Version, s.Name)
+			}
+		}
+		return
+	}
+
+	applied, err := db.Migrate()
+	for _, m := range applied {
+		fmt.Printf("%s Applied %04d_%s\n",
+			styles.SuccessMessage.Render("✓"), m.Version, m.Name)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+
+	if len(applied) == 0 {
+		fmt.Printf("%s Database is up to date\n",
+			styles.SuccessMessage.Render("✓"))
+	}
+}
+
+// GetDBCmd returns the db command
+func GetDBCmd() *cobra.Command {
+	return dbCmd
+}
+
+func init() {
+	dbMigrateCmd.Flags().Bool("status", false, "Show applied and pending migrations without applying them")
+	dbCmd.AddCommand(dbMigrateCmd)
+}
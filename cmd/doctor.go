@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jhoffmann/bookmark-manager/internal/app"
+	"github.com/jhoffmann/bookmark-manager/internal/service"
+	"github.com/jhoffmann/bookmark-manager/internal/tui/styles"
+	"github.com/spf13/cobra"
+)
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Find bookmarks pointing to missing or duplicate folders",
+	Long: `Check every bookmarked folder on disk and report:
+- missing folders, broken symlinks and paths that are not directories
+- folders that exist but cannot be read
+- duplicate bookmarks that differ only by a trailing slash or a symlink
+- paths with a trailing slash or other redundant elements
+
+With --fix, missing, broken and duplicate bookmarks are moved to the trash
+(keeping the oldest of each set of duplicates) and unclean paths are relinked
+to their cleaned form. Unreadable folders are only reported.
+
+Exits with status 1 when problems remain.
+
+Examples:
+  bookmark-manager doctor
+  bookmark-manager doctor --fix`,
+	Args: cobra.NoArgs,
+	Run:  runDoctor,
+}
+
+func runDoctor(cmd *cobra.Command, args []string) {
+	fix, _ := cmd.Flags().GetBool("fix")
+
+	// Initialize app (loads config, database, and service)
+	appInstance := app.InitializeOrExit()
+	defer appInstance.Close()
+
+	bookmarks, err := appInstance.Service.List(0, 0)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s Failed to list bookmarks: %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+
+	problems := service.NewFolders().Diagnose(bookmarks)
+	if len(problems) == 0 {
+		fmt.Printf("%s All %d bookmarks look healthy\n",
+			styles.SuccessMessage.Render("✓"), len(bookmarks))
+		return
+	}
+
+	remaining := 0
+	for _, p := range problems {
+		b := p.Bookmark
+
+		switch {
+		case p.DuplicateOf != nil:
+			fmt.Printf("%s Duplicate: %s (ID %d) is the same folder as %s (ID %d)\n",
+				styles.WarningMessage.Render("!"), b.Folder, b.ID, p.DuplicateOf.Folder, p.DuplicateOf.ID)
+		case p.CleanPath != "":
+			fmt.Printf("%s Unclean path: %s (ID %d) should be %s\n",
+				styles.WarningMessage.Render("!"), b.Folder, b.ID, p.CleanPath)
+		default:
+			fmt.Printf("%s %s: %s (ID %d)\n",
+				styles.ErrorMessage.Render("✗"), capitalize(string(p.Status)), b.Folder, b.ID)
+		}
+
+		if !fix || p.Status == service.FolderUnreadable {
+			remaining++
+			continue
+		}
+
+		if p.CleanPath != "" {
+			b.Folder = p.CleanPath
+			err = appInstance.Service.Save(b)
+		} else {
+			err = appInstance.Service.Delete(b)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s Failed to fix bookmark %d: %v\n",
+				styles.ErrorMessage.Render("✗"), b.ID, err)
+			remaining++
+			continue
+		}
+
+		if p.CleanPath != "" {
+			fmt.Printf("  %s Relinked to %s\n", styles.SuccessMessage.Render("✓"), p.CleanPath)
+		} else {
+			fmt.Printf("  %s Moved to trash\n", styles.SuccessMessage.Render("✓"))
+		}
+	}
+
+	if remaining > 0 {
+		if !fix {
+			fmt.Printf("\n%d problem(s) found; run with --fix to repair them\n", remaining)
+		}
+		os.Exit(1)
+	}
+}
+
+// capitalize upper-cases the first letter of an ASCII string
+func capitalize(s string) string {
+	if s == "" || s[0] < 'a' || s[0] > 'z' {
+		return s
+	}
+	return string(s[0]-'a'+'A') + s[1:]
+}
+
+// GetDoctorCmd returns the doctor command
+func GetDoctorCmd() *cobra.Command {
+	return doctorCmd
+}
+
+func init() {
+	doctorCmd.Flags().Bool("fix", false, "Trash missing and duplicate bookmarks and relink unclean paths")
+}
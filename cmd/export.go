@@ -1,13 +1,13 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
-	"strings"
+	"time"
 
 	"github.com/jhoffmann/bookmark-manager/internal/app"
 	"github.com/jhoffmann/bookmark-manager/internal/models"
+	"github.com/jhoffmann/bookmark-manager/internal/service"
 	"github.com/jhoffmann/bookmark-manager/internal/tui/styles"
 	"github.com/spf13/cobra"
 )
@@ -18,24 +18,31 @@ var exportCmd = &cobra.Command{
 	Short: "Export bookmarks to JSON",
 	Long: `Export bookmarks to JSON format. Output is written to stdout for piping.
 
+The filter is fuzzy-matched against folders and tags like the list view's
+filter, so "bmgr" matches bookmark-manager. --query selects bookmarks with the
+query language shared with list and jump.
+
+` + queryHelp + `
+
 Examples:
   bookmark-manager export > all-bookmarks.json
   bookmark-manager export work > work-bookmarks.json
   bookmark-manager export personal home > personal-home-bookmarks.json
-  bookmark-manager export "" projects > project-bookmarks.json`,
-	Args: cobra.MaximumNArgs(2),
-	Run:  runExport,
-}
-
-// ExportBookmark represents the JSON structure for exported bookmarks
-type ExportBookmark struct {
-	ID          uint   `json:"id"`
-	Folder      string `json:"folder"`
-	Category    string `json:"category"`
-	DateCreated string `json:"date_created"`
+  bookmark-manager export "" projects > project-bookmarks.json
+  bookmark-manager export --query 'cat:work -cat:archive added:<30d'`,
+	Args:              cobra.MaximumNArgs(2),
+	ValidArgsFunction: positional(completeTags, completeFilterTerms),
+	Run:               runExport,
 }
 
 func runExport(cmd *cobra.Command, args []string) {
+	q, err := queryFlag(cmd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+
 	// Initialize app (loads config, database, and service)
 	appInstance := app.InitializeOrExit()
 	defer appInstance.Close()
@@ -55,19 +62,19 @@ func runExport(cmd *cobra.Command, args []string) {
 	}
 
 	var bookmarks []*models.Bookmark
-	var err error
 
-	// Filter by category if specified
+	// Filter by category (or any other tag) if specified
 	if category != "" {
-		bookmarks, err = appInstance.Service.SearchByCategory(category)
+		bookmarks, err = appInstance.Service.SearchByTags([]string{string(category)})
 		if err != nil {
 			fmt.Printf("%s Failed to search bookmarks by category: %v\n",
 				styles.ErrorMessage.Render("✗"), err)
 			os.Exit(1)
 		}
+		bookmarks = q.Filter(bookmarks, time.Now())
 	} else {
-		// Get all bookmarks
-		bookmarks, err = appInstance.Service.List(0, 0)
+		// Get all bookmarks matching the query
+		bookmarks, err = appInstance.Service.Find(q, service.SortByCategory, 0, 0)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "%s Failed to list bookmarks: %v\n",
 				styles.ErrorMessage.Render("✗"), err)
@@ -75,36 +82,25 @@ func runExport(cmd *cobra.Command, args []string) {
 		}
 	}
 
-	// Apply filter if specified
+	// Apply filter if specified, using the same fuzzy matching as the list view
+	// but keeping the export in its usual order
 	if filter != "" {
-		filteredBookmarks := make([]*models.Bookmark, 0)
-		filterLower := strings.ToLower(filter)
+		matched := make(map[*models.Bookmark]bool)
+		for _, m := range service.FuzzyFilter(bookmarks, filter) {
+			matched[m.Bookmark] = true
+		}
 
+		filteredBookmarks := make([]*models.Bookmark, 0, len(matched))
 		for _, b := range bookmarks {
-			if strings.Contains(strings.ToLower(b.Folder), filterLower) ||
-				strings.Contains(strings.ToLower(string(b.Category)), filterLower) {
+			if matched[b] {
 				filteredBookmarks = append(filteredBookmarks, b)
 			}
 		}
 		bookmarks = filteredBookmarks
 	}
 
-	// Convert to export format
-	exportBookmarks := make([]ExportBookmark, len(bookmarks))
-	for i, b := range bookmarks {
-		exportBookmarks[i] = ExportBookmark{
-			ID:          b.ID,
-			Folder:      b.Folder,
-			Category:    string(b.Category),
-			DateCreated: b.DateCreated.Format("2006-01-02T15:04:05Z07:00"),
-		}
-	}
-
 	// Output JSON to stdout
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ") // Pretty print
-
-	if err := encoder.Encode(exportBookmarks); err != nil {
+	if err := service.WriteExport(os.Stdout, bookmarks); err != nil {
 		fmt.Fprintf(os.Stderr, "%s Failed to encode JSON: %v\n",
 			styles.ErrorMessage.Render("✗"), err)
 		os.Exit(1)
@@ -115,3 +111,7 @@ func runExport(cmd *cobra.Command, args []string) {
 func GetExportCmd() *cobra.Command {
 	return exportCmd
 }
+
+func init() {
+	addQueryFlag(exportCmd)
+}
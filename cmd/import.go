@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/jhoffmann/bookmark-manager/internal/app"
+	"github.com/jhoffmann/bookmark-manager/internal/models"
+	"github.com/jhoffmann/bookmark-manager/internal/service"
+	"github.com/jhoffmann/bookmark-manager/internal/tui/styles"
+	"github.com/spf13/cobra"
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import <file|->",
+	Short: "Import bookmarks from JSON",
+	Long: `Import bookmarks from the JSON format produced by the export command.
+Use "-" to read from stdin.
+
+Bookmarks whose folder already exists are skipped when the category matches,
+and reported as conflicts (without being modified) when it differs. Folders
+in the trash are skipped too; restore them instead. The original creation
+date, category and tags of each bookmark are preserved.
+
+Examples:
+  bookmark-manager import all-bookmarks.json
+  bookmark-manager export work | bookmark-manager import -`,
+	Args: cobra.ExactArgs(1),
+	Run:  runImport,
+}
+
+func runImport(cmd *cobra.Command, args []string) {
+	// Read input from file or stdin
+	var data []byte
+	var err error
+	if args[0] == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(args[0])
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s Failed to read input: %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+
+	var exported []service.ExportBookmark
+	if err := json.Unmarshal(data, &exported); err != nil {
+		fmt.Fprintf(os.Stderr, "%s Failed to decode JSON: %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+
+	// Convert from export format
+	bookmarks := make([]*models.Bookmark, len(exported))
+	for i, e := range exported {
+		bookmark := &models.Bookmark{
+			Folder:   e.Folder,
+			Alias:    models.NormalizeAlias(e.Alias),
+			Category: models.CategoryType(e.Category),
+		}
+		bookmark.SetTagNames(e.Tags)
+		if e.DateCreated != "" {
+			dateCreated, err := time.Parse(time.RFC3339, e.DateCreated)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s Invalid date_created for %s: %v\n",
+					styles.ErrorMessage.Render("✗"), e.Folder, err)
+				os.Exit(1)
+			}
+			bookmark.DateCreated = dateCreated
+		}
+		bookmarks[i] = bookmark
+	}
+
+	// Initialize app (loads config, database, and service)
+	appInstance := app.InitializeOrExit()
+	defer appInstance.Close()
+
+	result, err := appInstance.Service.Import(bookmarks)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+
+	for _, b := range result.Conflicts {
+		fmt.Printf("%s Conflict: %s [%s] already bookmarked with a different category\n",
+			styles.WarningMessage.Render("!"),
+			b.Folder,
+			b.Category)
+	}
+
+	fmt.Printf("%s Imported bookmarks: %d created, %d skipped, %d conflicting\n",
+		styles.SuccessMessage.Render("✓"),
+		result.Created,
+		result.Skipped,
+		len(result.Conflicts))
+}
+
+// GetImportCmd returns the import command
+func GetImportCmd() *cobra.Command {
+	return importCmd
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/jhoffmann/bookmark-manager/internal/tui/styles"
+	"github.com/spf13/cobra"
+)
+
+//go:embed shell/*.tmpl
+var shellTemplates embed.FS
+
+// shells lists the supported shells with the quoting used for the executable
+var shells = map[string]func(string) string{
+	"bash":       quotePOSIX,
+	"zsh":        quotePOSIX,
+	"fish":       quoteFish,
+	"powershell": quotePowerShell,
+	"nushell":    quoteNushell,
+}
+
+// shellScriptData is passed to the shell script templates
+type shellScriptData struct {
+	Exe        string // Quoted path of this executable
+	Cmd        string // Name of the cd wrapper; the jump and add helpers are suffixed
+	Hook       bool   // Whether to report directory changes with 'visit'
+	Completion string // Generated completion script, if the shell supports it
+}
+
+// initCmd represents the init command
+var initCmd = &cobra.Command{
+	Use:   "init <bash|zsh|fish|powershell|nushell>",
+	Short: "Print shell integration for cd wrappers, completion and visit tracking",
+	Long: `Print a script to evaluate in your shell's startup file. It defines:
+  bm     pick a bookmark in the TUI and cd to it (arguments are passed to pick),
+         or cd to @alias[/path] (see resolve)
+  bmj    cd to the bookmark best matching the query terms (see jump)
+  bma    bookmark the current directory (arguments are passed to add)
+and sets up completion for bookmark-manager and bm, which completes @aliases
+and the directories below them. The names can be changed with
+--cmd, e.g. --cmd b defines b, bj and ba.
+
+bm, bmj and @aliases record a visit to the bookmark they cd to. With --hook,
+every other directory change is reported with 'visit' so bookmarks you cd into
+by any means rank higher in frecency ordering and jump.
+
+Setup:
+  bash        eval "$(bookmark-manager init bash)"               # ~/.bashrc
+  zsh         eval "$(bookmark-manager init zsh)"                # ~/.zshrc
+  fish        bookmark-manager init fish | source                # config.fish
+  powershell  Invoke-Expression (& bookmark-manager init powershell | Out-String)
+  nushell     bookmark-manager init nushell | save -f ~/.bookmark-manager.nu
+              then add 'source ~/.bookmark-manager.nu' to config.nu`,
+	ValidArgs: []string{"bash", "zsh", "fish", "powershell", "nushell"},
+	Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	Run:       runInit,
+}
+
+func runInit(cmd *cobra.Command, args []string) {
+	name, _ := cmd.Flags().GetString("cmd")
+	hook, _ := cmd.Flags().GetBool("hook")
+
+	script, err := shellScript(cmd.Root(), args[0], name, hook)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+
+	fmt.Print(script)
+}
+
+// shellScript renders the integration script for a shell
+func shellScript(root *cobra.Command, shell, name string, hook bool) (string, error) {
+	quote, ok := shells[shell]
+	if !ok {
+		return "", fmt.Errorf("unsupported shell %q", shell)
+	}
+	if !isShellIdentifier(name) {
+		return "", fmt.Errorf("invalid command name %q", name)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate executable: %w", err)
+	}
+
+	completion, err := completionScript(root, shell)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate completion: %w", err)
+	}
+
+	tmpl, err := template.ParseFS(shellTemplates, "shell/"+shell+".tmpl")
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, shellScriptData{
+		Exe:        quote(exe),
+		Cmd:        name,
+		Hook:       hook,
+		Completion: strings.TrimRight(completion, "\n"),
+	}); err != nil {
+		return "", err
+	}
+	buf.WriteByte('\n')
+
+	return buf.String(), nil
+}
+
+// completionScript generates cobra's completion script for the shell; nushell
+// has no generator
+func completionScript(root *cobra.Command, shell string) (string, error) {
+	var buf bytes.Buffer
+	var err error
+	switch shell {
+	case "bash":
+		err = root.GenBashCompletionV2(&buf, true)
+	case "zsh":
+		err = root.GenZshCompletion(&buf)
+	case "fish":
+		err = root.GenFishCompletion(&buf, true)
+	case "powershell":
+		err = root.GenPowerShellCompletionWithDesc(&buf)
+	}
+	return buf.String(), err
+}
+
+// isShellIdentifier reports whether name is usable as a function name in every
+// supported shell
+func isShellIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_':
+		case (r >= '0' && r <= '9' || r == '-') && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func quotePOSIX(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func quoteFish(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
+
+func quotePowerShell(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func quoteNushell(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// GetInitCmd returns the init command
+func GetInitCmd() *cobra.Command {
+	return initCmd
+}
+
+func init() {
+	initCmd.Flags().String("cmd", "bm", "Name of the cd wrapper; the jump and add helpers get a j and a suffix")
+	initCmd.Flags().Bool("hook", false, "Report directory changes so visited bookmarks rank higher")
+}
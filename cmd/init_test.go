@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestShellScript(t *testing.T) {
+	root := &cobra.Command{Use: "bookmark-manager"}
+	root.AddCommand(&cobra.Command{Use: "list", Run: func(*cobra.Command, []string) {}})
+
+	for shell := range shells {
+		t.Run(shell, func(t *testing.T) {
+			script, err := shellScript(root, shell, "b", true)
+			if err != nil {
+				t.Fatalf("shellScript() error = %v", err)
+			}
+			for _, want := range []string{"# b:", "# bj:", "# ba:", " visit ", " pick ", " jump ", " add "} {
+				if !strings.Contains(script, want) {
+					t.Errorf("%s script does not contain %q", shell, want)
+				}
+			}
+
+			withoutHook, err := shellScript(root, shell, "b", false)
+			if err != nil {
+				t.Fatalf("shellScript() error = %v", err)
+			}
+			if strings.Contains(withoutHook, " visit ") {
+				t.Errorf("%s script without --hook should not report visits", shell)
+			}
+		})
+	}
+
+	if _, err := shellScript(root, "tcsh", "bm", false); err == nil {
+		t.Error("Expected an error for an unsupported shell")
+	}
+	if _, err := shellScript(root, "bash", "bm; rm -rf ~", false); err == nil {
+		t.Error("Expected an error for an invalid command name")
+	}
+}
+
+func TestQuotePOSIX(t *testing.T) {
+	if got, want := quotePOSIX("/opt/it's here/bm"), `'/opt/it'\''s here/bm'`; got != want {
+		t.Errorf("quotePOSIX() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jhoffmann/bookmark-manager/internal/app"
+	"github.com/jhoffmann/bookmark-manager/internal/service"
+	"github.com/jhoffmann/bookmark-manager/internal/tui/styles"
+	"github.com/spf13/cobra"
+)
+
+// jumpCmd represents the jump command
+var jumpCmd = &cobra.Command{
+	Use:   "jump <query...>",
+	Short: "Print the bookmark that best matches the query",
+	Long: `Score bookmarks against the query terms and print the best match without
+launching the TUI. Terms are matched case-insensitively against the folder
+basename, the other path segments and the categories (tags); every term must
+match. Frequently and recently visited bookmarks rank higher.
+
+When the best match is the current directory, the next best match is printed
+instead so repeated jumps don't go nowhere. The printed bookmark is recorded as
+visited.
+
+Exits with status 1 when nothing matches or the query is empty.
+
+Examples:
+  cd "$(bookmark-manager jump api)"
+  bookmark-manager jump work api
+  bookmark-manager jump --all src
+  bookmark-manager jump --query 'cat:work' api`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeFilterTerms,
+	Run:               runJump,
+}
+
+func runJump(cmd *cobra.Command, args []string) {
+	showAll, _ := cmd.Flags().GetBool("all")
+
+	if strings.TrimSpace(strings.Join(args, "")) == "" {
+		fmt.Fprintf(os.Stderr, "%s The query is empty\n",
+			styles.ErrorMessage.Render("✗"))
+		os.Exit(1)
+	}
+
+	q, err := queryFlag(cmd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+
+	// Initialize app (loads config, database, and service)
+	appInstance := app.InitializeOrExit()
+	defer appInstance.Close()
+
+	bookmarks, err := appInstance.Service.Find(q, service.SortByCategory, 0, 0)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s Failed to list bookmarks: %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+
+	matches := service.RankMatches(bookmarks, args, time.Now())
+	if len(matches) == 0 {
+		fmt.Fprintf(os.Stderr, "%s No bookmark matches: %s\n",
+			styles.WarningMessage.Render("!"), strings.Join(args, " "))
+		os.Exit(1)
+	}
+
+	if showAll {
+		for _, m := range matches {
+			fmt.Println(m.Bookmark.Folder)
+		}
+		return
+	}
+
+	best := matches[0]
+	if cwd, err := os.Getwd(); err == nil && best.Bookmark.Folder == cwd && len(matches) > 1 {
+		best = matches[1]
+	}
+
+	// Failing to record the visit should not stop the jump
+	_ = appInstance.Service.RecordVisit(best.Bookmark)
+
+	fmt.Println(best.Bookmark.Folder)
+}
+
+// GetJumpCmd returns the jump command
+func GetJumpCmd() *cobra.Command {
+	jumpCmd.Flags().BoolP("all", "a", false, "Print all matching bookmarks, best first")
+	addQueryFlag(jumpCmd)
+	return jumpCmd
+}
@@ -3,11 +3,18 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"runtime"
+	"text/template"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/jhoffmann/bookmark-manager/internal/app"
+	"github.com/jhoffmann/bookmark-manager/internal/config"
+	"github.com/jhoffmann/bookmark-manager/internal/service"
+	"github.com/jhoffmann/bookmark-manager/internal/tui/keys"
 	"github.com/jhoffmann/bookmark-manager/internal/tui/list"
 	"github.com/jhoffmann/bookmark-manager/internal/tui/styles"
+	"github.com/muesli/termenv"
 	"github.com/spf13/cobra"
 )
 
@@ -18,47 +25,157 @@ var listCmd = &cobra.Command{
 	Long: `Launch an interactive TUI to browse, filter, and manage your bookmarks.
 
 Features:
-- Tab through categories (All and custom categories)
+- Tab through categories (All and custom categories), shown with their number
+  of bookmarks in a tab bar; keys 1-9 jump to a tab and, fullscreen, clicking
+  a tab switches to it
 - Real-time filtering with '/' key
-- Delete bookmarks with 'x' key (with confirmation)
-- Open folders with 'o' or 'enter' key
+- Delete bookmarks with 'x' or 'd' key (with confirmation)
+- Open folders with 'enter' key
+- Edit a bookmark's category with 'e' and its alias with 'a'
+- Filter by alias by starting a filter term with '@'
+- Toggle frecency ordering (most used folders first) with 's' key
+- Restore deleted bookmarks from the Trash tab with 'r' key
+- Undo and redo deletes, category and alias changes with 'u' and 'ctrl+r'
+- Select several bookmarks with space, or all shown ones with 'ctrl+a', to
+  delete, restore, re-categorize or export ('E') them at once
+- Rename or merge the category of the active tab with 'R' and remove it from
+  its bookmarks with 'X'
 - Full keyboard navigation
 
+With --print (or the pick command) the TUI is drawn on the terminal and enter
+prints the selected folder to stdout instead of opening it, so the list can be
+used in command substitutions; the choice is recorded as a visit, as when
+opening it. Space marks several bookmarks to print at once, one per line, and
+--template formats each one (see ls). Quitting without a selection exits with
+status 1.
+
+With --height the TUI is drawn inline below the prompt, using that many lines
+or a percentage of the terminal, and is cleared again on exit instead of taking
+over the whole screen. The height setting (or BM_HEIGHT) sets the default.
+
+The default sort order and the command opening folders come from the sort and
+opener settings (see config). Keys are configurable too: the keys.preset
+setting picks the default, vim or emacs bindings and [keys.list],
+[keys.confirm] and [keys.edit] tables rebind single actions. Keys bound to
+several actions are reported at startup, and '?' shows the effective bindings.
+The colors come from the theme and background settings; NO_COLOR or
+--no-color turn them off.
+
 Examples:
   bookmark-manager list
   bookmark-manager list work
-  bookmark-manager list personal`,
-	Args: cobra.MaximumNArgs(1),
-	Run:  runList,
+  bookmark-manager list personal
+  bookmark-manager list --sort frecency
+  bookmark-manager list --query 'path:~/src -cat:archive'
+  cd "$(bookmark-manager list --print)"
+  cd "$(bookmark-manager pick --height 40%)"
+
+` + queryHelp,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: positional(completeTags),
+	Run:               runList,
 }
 
 func runList(cmd *cobra.Command, args []string) {
 	// Get flag values
 	cwdFile, _ := cmd.Flags().GetString("cwd-file")
+	printMode, _ := cmd.Flags().GetBool("print")
+	tmplText, _ := cmd.Flags().GetString("template")
+	printMode = printMode || cmd.Name() == "pick"
+
+	q, err := queryFlag(cmd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+
+	var tmpl *template.Template
+	if tmplText != "" {
+		if tmpl, err = parseBookmarkTemplate(tmplText); err != nil {
+			fmt.Fprintf(os.Stderr, "%s %v\n",
+				styles.ErrorMessage.Render("✗"), err)
+			os.Exit(1)
+		}
+	}
 
 	// Initialize app (loads config, database, and service)
 	appInstance := app.InitializeOrExit()
 	defer appInstance.Close()
 
-	// Parse arguments
-	var initialCategory string
+	sortOrder, err := sortOrderFlag(cmd, appInstance.Config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
 
-	if len(args) > 0 && args[0] != "" {
+	heightText := appInstance.Config.GetHeight()
+	if cmd.Flags().Changed("height") {
+		heightText, _ = cmd.Flags().GetString("height")
+	}
+	height, err := list.ParseHeight(heightText)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+
+	bindings, err := keys.Load(appInstance.Config.GetKeys())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s invalid key bindings:\n%v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+
+	// Parse arguments; without a category all bookmarks are shown
+	var initialCategory string
+	if len(args) > 0 {
 		initialCategory = args[0]
-	} else {
-		initialCategory = "All"
 	}
 
 	// Create TUI model
 	model := list.New(appInstance.Service, initialCategory)
+	model.SetSortOrder(sortOrder)
+	model.SetQuery(q)
+	model.SetHeight(height)
+	model.SetOpener(appInstance.Config.GetOpener())
+	model.SetKeys(bindings)
 
 	// Set cwd file mode if flag is provided
 	if cwdFile != "" {
 		model.SetCwdFile(cwdFile)
 	}
 
+	// Inline, the TUI is drawn below the prompt and cleared on exit
+	var options []tea.ProgramOption
+	// Fullscreen, the mouse switches tabs by clicking them; inline it is left
+	// to the terminal so text can still be selected
+	if height.Fullscreen() {
+		options = append(options, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	}
+
+	// In print mode stdout carries the selection, so the TUI is drawn on the
+	// terminal directly
+	if printMode {
+		model.SetPrintMode(true)
+
+		ttyIn, ttyOut, err := openTTY()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s Failed to open the terminal: %v\n",
+				styles.ErrorMessage.Render("✗"), err)
+			os.Exit(1)
+		}
+		defer ttyIn.Close()
+		defer ttyOut.Close()
+
+		// Detect colors from the terminal rather than the redirected stdout
+		lipgloss.DefaultRenderer().SetOutput(termenv.NewOutput(ttyOut))
+		options = append(options, tea.WithInput(ttyIn), tea.WithOutput(ttyOut))
+	}
+
 	// Create Bubble Tea program
-	program := tea.NewProgram(model, tea.WithAltScreen())
+	program := tea.NewProgram(model, options...)
 
 	// Run the program
 	finalModel, err := program.Run()
@@ -68,13 +185,84 @@ func runList(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	// Handle any final state from the model - no additional handling needed
-	// as the model writes to the file directly when a selection is made
-	_ = finalModel
+	// The TUI has cleared its notifications, so what failed as it quit is
+	// reported here
+	if warning := finalModel.(list.Model).Warning(); warning != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n",
+			styles.WarningMessage.Render("!"), warning)
+	}
+
+	// In cwd-file mode the model writes to the file directly when a selection
+	// is made; in print mode the selection is printed here
+	if !printMode {
+		return
+	}
+
+	selection := finalModel.(list.Model).Selection()
+	if len(selection) == 0 {
+		// Cancelled
+		os.Exit(1)
+	}
+
+	if tmpl != nil {
+		err = printTemplate(os.Stdout, selection, tmpl)
+	} else {
+		err = printBookmarks(os.Stdout, selection, "plain", false)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+}
+
+// openTTY opens the controlling terminal for reading and writing
+func openTTY() (in, out *os.File, err error) {
+	if runtime.GOOS == "windows" {
+		if in, err = os.OpenFile("CONIN$", os.O_RDWR, 0); err != nil {
+			return nil, nil, err
+		}
+		if out, err = os.OpenFile("CONOUT$", os.O_RDWR, 0); err != nil {
+			in.Close()
+			return nil, nil, err
+		}
+		return in, out, nil
+	}
+
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tty, tty, nil
+}
+
+// addSortFlag registers the --sort flag, which defaults to the sort setting
+func addSortFlag(cmd *cobra.Command) {
+	cmd.Flags().String("sort", "", "Sort order: category or frecency (default from the sort setting, or category)")
+	_ = cmd.RegisterFlagCompletionFunc("sort", fixedCompletions(string(service.SortByCategory), string(service.SortByFrecency)))
+}
+
+// sortOrderFlag returns the sort order given with --sort, or the configured one
+func sortOrderFlag(cmd *cobra.Command, cfg *config.Config) (service.SortOrder, error) {
+	value := cfg.GetSort()
+	if cmd.Flags().Changed("sort") {
+		value, _ = cmd.Flags().GetString("sort")
+	}
+	return service.ParseSortOrder(value)
+}
+
+// addListFlags registers the flags shared by list and pick
+func addListFlags(cmd *cobra.Command) {
+	addSortFlag(cmd)
+	cmd.Flags().String("height", "", "Draw the TUI inline using this many lines or a percentage of the terminal, e.g. 40%")
+	cmd.Flags().StringP("template", "t", "", "Go template to print each selected bookmark with, e.g. '{{.ID}} {{.Folder}}'")
+	addQueryFlag(cmd)
 }
 
 // GetListCmd returns the list command
 func GetListCmd() *cobra.Command {
 	listCmd.Flags().String("cwd-file", "", "Write the selection to the specified file and exit")
+	listCmd.Flags().BoolP("print", "p", false, "Draw the TUI on the terminal and print the selected folders to stdout")
+	addListFlags(listCmd)
 	return listCmd
 }
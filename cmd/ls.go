@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
+	"github.com/jhoffmann/bookmark-manager/internal/app"
+	"github.com/jhoffmann/bookmark-manager/internal/models"
+	"github.com/jhoffmann/bookmark-manager/internal/service"
+	"github.com/jhoffmann/bookmark-manager/internal/tui/styles"
+	"github.com/spf13/cobra"
+)
+
+// lsFormats are the output formats supported by ls
+var lsFormats = []string{"table", "plain", "json", "ndjson", "tsv"}
+
+// lsCmd represents the ls command
+var lsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "Print bookmarks without launching the TUI",
+	Long: `Print bookmarks to stdout for use in scripts.
+
+Formats:
+  table   aligned columns with a header (default)
+  plain   one folder per line
+  json    a JSON array in the export format
+  ndjson  one JSON object per line in the export format
+  tsv     tab-separated id, folder, category, tags, date added, visits and alias
+
+--template renders each bookmark with a Go template instead, e.g.
+'{{.ID}} {{.Folder}}'. The fields are those of a bookmark (ID, Folder, Alias,
+Category, DateCreated, VisitCount, LastVisited) and .TagNames; the join
+function joins a list, e.g. '{{join .TagNames ","}}'.
+
+Colors are only used when stdout is a terminal.
+
+` + queryHelp + `
+
+Examples:
+  bookmark-manager ls
+  bookmark-manager ls --category work --format plain
+  bookmark-manager ls --query 'name:api* added:<30d' --format json
+  bookmark-manager ls --sort frecency --template '{{.Folder}}' | fzf`,
+	Args: cobra.NoArgs,
+	Run:  runLs,
+}
+
+func runLs(cmd *cobra.Command, args []string) {
+	category, _ := cmd.Flags().GetString("category")
+	format, _ := cmd.Flags().GetString("format")
+	tmplText, _ := cmd.Flags().GetString("template")
+
+	fail := func(err error) {
+		fmt.Fprintf(os.Stderr, "%s %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+
+	q, err := queryFlag(cmd)
+	if err != nil {
+		fail(err)
+	}
+
+	var tmpl *template.Template
+	if tmplText != "" {
+		tmpl, err = parseBookmarkTemplate(tmplText)
+		if err != nil {
+			fail(err)
+		}
+	} else if !isLsFormat(format) {
+		fail(fmt.Errorf("unknown format %q (expected one of %s)", format, strings.Join(lsFormats, ", ")))
+	}
+
+	// Initialize app (loads config, database, and service)
+	appInstance := app.InitializeOrExit()
+	defer appInstance.Close()
+
+	sortOrder, err := sortOrderFlag(cmd, appInstance.Config)
+	if err != nil {
+		fail(err)
+	}
+
+	var bookmarks []*models.Bookmark
+	if category != "" {
+		bookmarks, err = appInstance.Service.SearchByCategory(models.CategoryType(category))
+		if err == nil {
+			bookmarks = q.Filter(bookmarks, time.Now())
+			if sortOrder == service.SortByFrecency {
+				service.SortByFrecencyScore(bookmarks, time.Now())
+			}
+		}
+	} else {
+		bookmarks, err = appInstance.Service.Find(q, sortOrder, 0, 0)
+	}
+	if err != nil {
+		fail(err)
+	}
+
+	if tmpl != nil {
+		err = printTemplate(os.Stdout, bookmarks, tmpl)
+	} else {
+		err = printBookmarks(os.Stdout, bookmarks, format, term.IsTerminal(os.Stdout.Fd()))
+	}
+	if err != nil {
+		fail(err)
+	}
+}
+
+// isLsFormat reports whether format is one of lsFormats
+func isLsFormat(format string) bool {
+	for _, f := range lsFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// printBookmarks writes the bookmarks in the given format; color is only used
+// for the table header
+func printBookmarks(w io.Writer, bookmarks []*models.Bookmark, format string, color bool) error {
+	switch format {
+	case "plain":
+		for _, b := range bookmarks {
+			if _, err := fmt.Fprintln(w, b.Folder); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case "json":
+		return service.WriteExport(w, bookmarks)
+
+	case "ndjson":
+		encoder := json.NewEncoder(w)
+		for _, b := range bookmarks {
+			if err := encoder.Encode(service.NewExportBookmark(b)); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case "tsv":
+		for _, b := range bookmarks {
+			fields := []string{
+				strconv.FormatUint(uint64(b.ID), 10),
+				tsvField(b.Folder),
+				tsvField(string(b.Category)),
+				tsvField(strings.Join(b.TagNames(), ",")),
+				b.DateCreated.Format(time.RFC3339),
+				strconv.Itoa(b.VisitCount),
+				tsvField(b.Alias),
+			}
+			if _, err := fmt.Fprintln(w, strings.Join(fields, "\t")); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	default:
+		return printTable(w, bookmarks, color)
+	}
+}
+
+// printTable writes the bookmarks as aligned columns under a header
+func printTable(w io.Writer, bookmarks []*models.Bookmark, color bool) error {
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "ID\tALIAS\tCATEGORY\tTAGS\tVISITS\tADDED\tFOLDER")
+	for _, b := range bookmarks {
+		alias := "-"
+		if b.Alias != "" {
+			alias = models.AliasPrefix + b.Alias
+		}
+		category := string(b.Category)
+		if category == "" {
+			category = "-"
+		}
+		// The category is shown in its own column, so only list the other tags
+		var extra []string
+		for _, tag := range b.TagNames() {
+			if tag != string(b.Category) {
+				extra = append(extra, tag)
+			}
+		}
+		tags := strings.Join(extra, ",")
+		if tags == "" {
+			tags = "-"
+		}
+
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%d\t%s\t%s\n",
+			b.ID, alias, category, tags, b.VisitCount, b.DateCreated.Format("2006-01-02"), b.Folder)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	// Style the header after alignment so escape codes don't skew the columns
+	header, rows, _ := strings.Cut(buf.String(), "\n")
+	if color {
+		header = lipgloss.NewStyle().Bold(true).Foreground(styles.Primary).Render(header)
+	}
+	_, err := fmt.Fprint(w, header+"\n"+rows)
+	return err
+}
+
+// parseBookmarkTemplate parses a template rendering a single bookmark
+func parseBookmarkTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("bookmark").Funcs(template.FuncMap{"join": strings.Join}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// printTemplate renders the template once per bookmark, adding a newline
+// unless the template ends with one
+func printTemplate(w io.Writer, bookmarks []*models.Bookmark, tmpl *template.Template) error {
+	for _, b := range bookmarks {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, b); err != nil {
+			return fmt.Errorf("failed to render template: %w", err)
+		}
+		if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tsvField replaces tabs and newlines, which would break the row structure
+func tsvField(s string) string {
+	return strings.NewReplacer("\t", " ", "\n", " ", "\r", " ").Replace(s)
+}
+
+// GetLsCmd returns the ls command
+func GetLsCmd() *cobra.Command {
+	return lsCmd
+}
+
+func init() {
+	lsCmd.Flags().StringP("category", "c", "", "Only print bookmarks in this category")
+	lsCmd.Flags().StringP("format", "f", "table", "Output format: "+strings.Join(lsFormats, ", "))
+	lsCmd.Flags().StringP("template", "t", "", "Go template to render each bookmark with, e.g. '{{.Folder}}'")
+	addSortFlag(lsCmd)
+	addQueryFlag(lsCmd)
+
+	_ = lsCmd.RegisterFlagCompletionFunc("category", completeTags)
+	_ = lsCmd.RegisterFlagCompletionFunc("format", fixedCompletions(lsFormats...))
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// pickCmd represents the pick command
+var pickCmd = &cobra.Command{
+	Use:   "pick [category]",
+	Short: "Choose bookmarks in the TUI and print them to stdout",
+	Long: `Same as 'list --print': the TUI is drawn on the terminal and enter prints the
+selected folder to stdout, recording a visit to it. Space marks several
+bookmarks to print at once, one per line. Quitting without a selection exits
+with status 1.
+
+Examples:
+  cd "$(bookmark-manager pick)"
+  vim "$(bookmark-manager pick work)/main.go"
+  bookmark-manager pick --template '{{.ID}}' | xargs bookmark-manager rm`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: positional(completeTags),
+	Run:               runList,
+}
+
+// GetPickCmd returns the pick command
+func GetPickCmd() *cobra.Command {
+	return pickCmd
+}
+
+func init() {
+	addListFlags(pickCmd)
+}
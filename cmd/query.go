@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"github.com/jhoffmann/bookmark-manager/internal/query"
+	"github.com/spf13/cobra"
+)
+
+// queryHelp documents the query syntax in the help of commands taking --query
+const queryHelp = `Queries combine terms with AND (implicit), OR, NOT, "-" and parentheses:
+  cat:work / tag:go      bookmark has the tag (wildcards: cat:arch*)
+  path:~/src             folder contains the path (wildcards match the whole path)
+  name:api*              last element of the folder matches
+  added:<30d / >2w       added less / more than an age ago
+  added:<2024-01-01      added before, after (>) or on a date
+  visited:<1d            last visited, like added:
+  word                   folder or any tag contains the word`
+
+// addQueryFlag registers the --query flag shared by commands that select bookmarks
+func addQueryFlag(cmd *cobra.Command) {
+	cmd.Flags().StringP("query", "q", "", "Only include bookmarks matching this query, e.g. 'cat:work -cat:archive added:<30d'")
+	_ = cmd.RegisterFlagCompletionFunc("query", completeQuery)
+}
+
+// queryFlag parses the --query flag, returning an empty query when it is not
+// set or not defined on the command
+func queryFlag(cmd *cobra.Command) (*query.Query, error) {
+	input, _ := cmd.Flags().GetString("query")
+	return query.Parse(input)
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jhoffmann/bookmark-manager/internal/app"
+	"github.com/jhoffmann/bookmark-manager/internal/models"
+	"github.com/jhoffmann/bookmark-manager/internal/tui/styles"
+	"github.com/spf13/cobra"
+)
+
+// resolveCmd represents the resolve command
+var resolveCmd = &cobra.Command{
+	Use:   "resolve <@alias[/path]>",
+	Short: "Print the folder a bookmark alias refers to",
+	Long: `Print the folder of the bookmark with the given alias, followed by the path
+after the alias if there is one, and record a visit to the bookmark. The
+leading @ may be omitted. Exits with status 1 when no bookmark has the alias.
+
+Examples:
+  bookmark-manager resolve @api
+  bookmark-manager resolve @api/cmd/server
+  cd "$(bookmark-manager resolve @api)"`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: positional(completeAliasRef),
+	Run:               runResolve,
+}
+
+func runResolve(cmd *cobra.Command, args []string) {
+	ref := args[0]
+	if !strings.HasPrefix(ref, models.AliasPrefix) {
+		ref = models.AliasPrefix + ref
+	}
+
+	// Initialize app (loads config, database, and service)
+	appInstance := app.InitializeOrExit()
+	defer appInstance.Close()
+
+	path, err := appInstance.Service.ResolveAlias(ref)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+
+	// Failing to record the visit should not stop the cd
+	alias, _, _ := models.ParseAliasRef(ref)
+	if bookmark, err := appInstance.Service.GetByAlias(alias); err == nil {
+		_ = appInstance.Service.RecordVisit(bookmark)
+	}
+
+	fmt.Println(path)
+}
+
+// GetResolveCmd returns the resolve command
+func GetResolveCmd() *cobra.Command {
+	return resolveCmd
+}
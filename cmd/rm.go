@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jhoffmann/bookmark-manager/internal/app"
+	"github.com/jhoffmann/bookmark-manager/internal/models"
+	"github.com/jhoffmann/bookmark-manager/internal/service"
+	"github.com/jhoffmann/bookmark-manager/internal/tui/styles"
+	"github.com/spf13/cobra"
+)
+
+// rmCmd represents the rm command
+var rmCmd = &cobra.Command{
+	Use:   "rm <id|@alias|path>...",
+	Short: "Move bookmarks to the trash",
+	Long: `Move bookmarks to the trash. Each argument is a bookmark ID, an @alias or
+the path of a bookmarked folder. Trashed bookmarks can be brought back with
+'trash restore'.
+
+Examples:
+  bookmark-manager rm 12
+  bookmark-manager rm @old
+  bookmark-manager rm ~/src/old-project
+  bookmark-manager rm .`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeBookmarkRefs,
+	Run:               runRm,
+}
+
+func runRm(cmd *cobra.Command, args []string) {
+	// Initialize app (loads config, database, and service)
+	appInstance := app.InitializeOrExit()
+	defer appInstance.Close()
+
+	failed := false
+	for _, arg := range args {
+		bookmark, err := resolveBookmark(appInstance.Service, arg)
+		if err == nil {
+			err = appInstance.Service.Delete(bookmark)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s %v\n",
+				styles.ErrorMessage.Render("✗"), err)
+			failed = true
+			continue
+		}
+
+		fmt.Printf("%s Moved to trash: %s [%s]\n",
+			styles.SuccessMessage.Render("✓"),
+			bookmark.Folder,
+			strings.Join(bookmark.TagNames(), ", "))
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// resolveBookmark looks up a bookmark by ID when the argument is numeric, by
+// alias when it starts with @ and by folder otherwise. Relative folders are
+// resolved against the current directory.
+func resolveBookmark(bookmarks *service.Bookmarks, arg string) (*models.Bookmark, error) {
+	if id, err := strconv.ParseUint(arg, 10, 0); err == nil && id > 0 {
+		return bookmarks.GetByID(uint(id))
+	}
+
+	if strings.HasPrefix(arg, models.AliasPrefix) {
+		return bookmarks.GetByAlias(arg)
+	}
+
+	absPath, err := filepath.Abs(arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	return bookmarks.GetByFolder(absPath)
+}
+
+// GetRmCmd returns the rm command
+func GetRmCmd() *cobra.Command {
+	return rmCmd
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jhoffmann/bookmark-manager/internal/app"
+	"github.com/jhoffmann/bookmark-manager/internal/models"
+	"github.com/jhoffmann/bookmark-manager/internal/tui/styles"
+	"github.com/spf13/cobra"
+)
+
+// setCategoryCmd represents the set-category command
+var setCategoryCmd = &cobra.Command{
+	Use:   "set-category <id|@alias|path> <category>",
+	Short: "Change the category of a bookmark",
+	Long: `Change the category (primary tag) of a bookmark. The bookmark is given by ID,
+by @alias or by the path of the bookmarked folder. An empty category removes it.
+
+Examples:
+  bookmark-manager set-category 12 work
+  bookmark-manager set-category ~/src/api personal
+  bookmark-manager set-category @api work
+  bookmark-manager set-category . ""`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: positional(completeBookmarkRefs, completeTags),
+	Run:               runSetCategory,
+}
+
+func runSetCategory(cmd *cobra.Command, args []string) {
+	// Initialize app (loads config, database, and service)
+	appInstance := app.InitializeOrExit()
+	defer appInstance.Close()
+
+	bookmark, err := resolveBookmark(appInstance.Service, args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+
+	bookmark.Category = models.CategoryType(strings.TrimSpace(args[1]))
+	if err := appInstance.Service.Save(bookmark); err != nil {
+		fmt.Fprintf(os.Stderr, "%s Failed to update bookmark: %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("%s Updated bookmark: %s [%s]\n",
+		styles.SuccessMessage.Render("✓"),
+		bookmark.Folder,
+		strings.Join(bookmark.TagNames(), ", "))
+}
+
+// GetSetCategoryCmd returns the set-category command
+func GetSetCategoryCmd() *cobra.Command {
+	return setCategoryCmd
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jhoffmann/bookmark-manager/internal/app"
+	"github.com/jhoffmann/bookmark-manager/internal/query"
+	"github.com/jhoffmann/bookmark-manager/internal/tui/styles"
+	"github.com/spf13/cobra"
+)
+
+// trashCmd represents the trash command
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "List, restore and purge deleted bookmarks",
+	Long: `Deleted bookmarks are kept in the trash until they are purged.
+
+Examples:
+  bookmark-manager trash list
+  bookmark-manager trash restore 12
+  bookmark-manager trash purge --older-than 30d`,
+	Args: cobra.NoArgs,
+	Run:  runTrashList,
+}
+
+// trashListCmd represents the trash list command
+var trashListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List deleted bookmarks",
+	Args:  cobra.NoArgs,
+	Run:   runTrashList,
+}
+
+// trashRestoreCmd represents the trash restore command
+var trashRestoreCmd = &cobra.Command{
+	Use:               "restore <id>...",
+	Short:             "Restore deleted bookmarks",
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeTrashedIDs,
+	Run:               runTrashRestore,
+}
+
+// trashPurgeCmd represents the trash purge command
+var trashPurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Permanently delete bookmarks in the trash",
+	Long: `Permanently delete bookmarks in the trash. With --older-than, only bookmarks
+deleted longer ago than the given age are purged. Ages accept Go durations
+(e.g. 36h) as well as days and weeks (e.g. 30d, 2w).`,
+	Args: cobra.NoArgs,
+	Run:  runTrashPurge,
+}
+
+func runTrashList(cmd *cobra.Command, args []string) {
+	// Initialize app (loads config, database, and service)
+	appInstance := app.InitializeOrExit()
+	defer appInstance.Close()
+
+	bookmarks, err := appInstance.Service.ListDeleted()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+
+	if len(bookmarks) == 0 {
+		fmt.Printf("%s Trash is empty\n", styles.SuccessMessage.Render("✓"))
+		return
+	}
+
+	for _, b := range bookmarks {
+		fmt.Printf("%4d  %s  %s [%s]\n",
+			b.ID,
+			b.DeletedAt.Time.Format("2006-01-02 15:04"),
+			b.Folder,
+			strings.Join(b.TagNames(), ", "))
+	}
+}
+
+func runTrashRestore(cmd *cobra.Command, args []string) {
+	ids, err := parseIDs(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+
+	// Initialize app (loads config, database, and service)
+	appInstance := app.InitializeOrExit()
+	defer appInstance.Close()
+
+	failed := false
+	for _, id := range ids {
+		restored, err := appInstance.Service.Restore(id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s %v\n",
+				styles.ErrorMessage.Render("✗"), err)
+			failed = true
+			continue
+		}
+		fmt.Printf("%s Restored bookmark: %s [%s]\n",
+			styles.SuccessMessage.Render("✓"),
+			restored.Folder,
+			strings.Join(restored.TagNames(), ", "))
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func runTrashPurge(cmd *cobra.Command, args []string) {
+	olderThanFlag, _ := cmd.Flags().GetString("older-than")
+
+	var olderThan time.Duration
+	if olderThanFlag != "" {
+		var err error
+		olderThan, err = query.ParseAge(olderThanFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s %v\n",
+				styles.ErrorMessage.Render("✗"), err)
+			os.Exit(1)
+		}
+	}
+
+	// Initialize app (loads config, database, and service)
+	appInstance := app.InitializeOrExit()
+	defer appInstance.Close()
+
+	count, err := appInstance.Service.Purge(olderThan)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("%s Purged %d bookmark(s) from the trash\n",
+		styles.SuccessMessage.Render("✓"), count)
+}
+
+// parseIDs converts bookmark ID arguments to numbers
+func parseIDs(args []string) ([]uint, error) {
+	ids := make([]uint, len(args))
+	for i, arg := range args {
+		id, err := strconv.ParseUint(arg, 10, 0)
+		if err != nil || id == 0 {
+			return nil, fmt.Errorf("invalid bookmark ID %q", arg)
+		}
+		ids[i] = uint(id)
+	}
+	return ids, nil
+}
+
+// GetTrashCmd returns the trash command
+func GetTrashCmd() *cobra.Command {
+	return trashCmd
+}
+
+func init() {
+	trashPurgeCmd.Flags().String("older-than", "", "Only purge bookmarks deleted longer ago than this age (e.g. 30d)")
+	trashCmd.AddCommand(trashListCmd, trashRestoreCmd, trashPurgeCmd)
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jhoffmann/bookmark-manager/internal/app"
+	"github.com/jhoffmann/bookmark-manager/internal/service"
+	"github.com/jhoffmann/bookmark-manager/internal/tui/styles"
+	"github.com/spf13/cobra"
+)
+
+// visitCmd represents the visit command
+var visitCmd = &cobra.Command{
+	Use:   "visit [path]",
+	Short: "Record a visit to a bookmarked folder",
+	Long: `Record a visit to the bookmark for the given folder (the current directory by
+default) so it ranks higher in frecency ordering and jump. Folders that are not
+bookmarked are silently ignored, which makes this suitable for a shell cd hook;
+see 'bookmark-manager init --hook'.
+
+Examples:
+  bookmark-manager visit
+  bookmark-manager visit ~/src/api`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runVisit,
+}
+
+func runVisit(cmd *cobra.Command, args []string) {
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s Failed to get absolute path: %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+
+	// Initialize app (loads config, database, and service)
+	appInstance := app.InitializeOrExit()
+	defer appInstance.Close()
+
+	bookmark, err := appInstance.Service.GetByFolder(absPath)
+	if errors.Is(err, service.ErrNotFound) {
+		return
+	}
+	if err == nil {
+		err = appInstance.Service.RecordVisit(bookmark)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n",
+			styles.ErrorMessage.Render("✗"), err)
+		os.Exit(1)
+	}
+}
+
+// GetVisitCmd returns the visit command
+func GetVisitCmd() *cobra.Command {
+	return visitCmd
+}
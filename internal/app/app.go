@@ -26,26 +26,30 @@ func (a *App) Close() error {
 // Initialize loads configuration, initializes database, and returns an App instance
 // This centralizes all the repetitive setup code from the command files
 func Initialize() (*App, error) {
-	// Load configuration
+	cfg, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return InitializeWithConfig(cfg)
+}
+
+// LoadConfig loads the configuration and styles the TUI and the messages with
+// its theme
+func LoadConfig() (*config.Config, error) {
 	cfg, err := config.Load()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load configuration: %w", err)
 	}
 
-	// Initialize database
-	db, err := database.NewDatabase(cfg)
+	theme, err := styles.Load(cfg.GetTheme(), cfg.GetThemes())
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
+		return nil, fmt.Errorf("invalid theme: %w", err)
 	}
+	styles.Apply(theme)
+	styles.SetBackground(cfg.GetBackground())
 
-	// Initialize bookmark service
-	bookmarkService := service.NewBookmarks(db)
-
-	return &App{
-		DB:      db,
-		Service: bookmarkService,
-		Config:  cfg,
-	}, nil
+	return cfg, nil
 }
 
 // InitializeOrExit initializes the app and exits on error with styled messages
@@ -69,7 +73,8 @@ func MustInitialize() *App {
 	return app
 }
 
-// InitializeWithConfig initializes with a specific configuration (useful for testing)
+// InitializeWithConfig initializes with a specific configuration, applying any
+// pending migrations
 func InitializeWithConfig(cfg *config.Config) (*App, error) {
 	// Initialize database
 	db, err := database.NewDatabase(cfg)
@@ -87,6 +92,22 @@ func InitializeWithConfig(cfg *config.Config) (*App, error) {
 	}, nil
 }
 
+// InitializeReadOnly is like InitializeWithConfig for commands that only read
+// the bookmarks: it opens the existing database for reading, without applying
+// migrations. The error wraps fs.ErrNotExist when there is no database yet.
+func InitializeReadOnly(cfg *config.Config) (*App, error) {
+	db, err := database.OpenReadOnly(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	return &App{
+		DB:      db,
+		Service: service.NewBookmarks(db),
+		Config:  cfg,
+	}, nil
+}
+
 // InitializeInMemory creates an in-memory database for testing
 func InitializeInMemory() (*App, error) {
 	cfg := &config.Config{
@@ -1,6 +1,7 @@
 // Package config provides configuration management for the bookmark manager application.
-// It handles loading configuration from environment variables and provides cross-platform
-// default paths for application data.
+// Settings are layered: defaults, then the TOML config file, then environment variables,
+// then command line flags, each overriding the previous one. It also provides
+// cross-platform default paths for application data.
 package config
 
 import (
@@ -12,20 +13,75 @@ import (
 
 // Config holds application configuration options
 type Config struct {
-	DatabasePath string `envconfig:"BM_DATABASE" json:"database_path"`
-	LogLevel     string `envconfig:"BM_LOGLEVEL" json:"log_level"`
+	DatabasePath    string           `toml:"database,omitempty"`
+	LogLevel        string           `toml:"log_level,omitempty"`
+	DefaultCategory string           `toml:"default_category,omitempty"` // Category of bookmarks added without one
+	Sort            string           `toml:"sort,omitempty"`             // Default sort order of list, pick and ls
+	Height          string           `toml:"height,omitempty"`           // Default inline height of the TUI, empty for fullscreen
+	Opener          []string         `toml:"opener,omitempty"`           // Command opening folders, the system file manager when empty
+	Theme           string           `toml:"theme,omitempty"`            // Color theme of the TUI and messages
+	Background      string           `toml:"background,omitempty"`       // Terminal background: auto, light or dark
+	Keys            KeyBindings      `toml:"keys,omitempty"`             // Key bindings by scope and action
+	Themes          map[string]Theme `toml:"themes,omitempty"`           // User themes by name
+
+	// origins records where each setting was last set from, see Origin
+	origins map[string]string
 }
 
-// Load loads configuration from environment variables with sensible defaults
+// Origins of settings reported by Origin
+const (
+	OriginDefault = "default"
+	OriginFile    = "file"
+	OriginFlag    = "flag"
+)
+
+// defaults returns the configuration used when nothing else is set
+func defaults() *Config {
+	return &Config{
+		LogLevel:   "warn",
+		Sort:       "category",
+		Background: "auto",
+	}
+}
+
+// Load loads the configuration, layering the config file, environment variables
+// and command line overrides (see Override) over the defaults
 func Load() (*Config, error) {
-	config := &Config{
-		LogLevel: "warn", // Default log level
+	config := defaults()
+
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	file, err := LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if file != nil {
+		config.merge(file, OriginFile)
+	}
+
+	// Environment variables
+	for _, s := range settings {
+		value := os.Getenv(s.env)
+		if value == "" {
+			continue
+		}
+		if err := s.set(config, value); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", s.env, err)
+		}
+		config.setOrigin(s.key, "env "+s.env)
 	}
 
-	// Load from environment variables
-	if dbPath := os.Getenv("BM_DATABASE"); dbPath != "" {
-		config.DatabasePath = dbPath
-	} else {
+	// Command line flags
+	for _, o := range overrides {
+		if err := Set(config, o.key, o.value); err != nil {
+			return nil, fmt.Errorf("invalid --%s: %w", o.flag, err)
+		}
+		config.setOrigin(o.key, OriginFlag)
+	}
+
+	if config.DatabasePath == "" {
 		// Use default path in user's config directory
 		defaultPath, err := getDefaultDatabasePath()
 		if err != nil {
@@ -34,20 +90,56 @@ func Load() (*Config, error) {
 		config.DatabasePath = defaultPath
 	}
 
-	if logLevel := os.Getenv("BM_LOGLEVEL"); logLevel != "" {
-		config.LogLevel = logLevel
+	return config, nil
+}
+
+// merge copies the settings defined in other over the configuration,
+// recording them as coming from origin
+func (c *Config) merge(other *Config, origin string) {
+	for _, s := range settings {
+		if value := s.get(other); value != "" {
+			_ = s.set(c, value)
+			c.setOrigin(s.key, origin)
+		}
 	}
+	for _, scope := range KeyScopes {
+		bindings, _ := other.Keys.Scope(scope)
+		for action, keys := range bindings {
+			c.Keys.bind(scope, action, keys)
+			c.setOrigin(KeysPrefix+scope+"."+action, origin)
+		}
+	}
+	for name, theme := range other.Themes {
+		if c.Themes == nil {
+			c.Themes = make(map[string]Theme)
+		}
+		c.Themes[name] = theme
+	}
+}
 
-	return config, nil
+// setOrigin records where a setting was set from
+func (c *Config) setOrigin(key, origin string) {
+	if c.origins == nil {
+		c.origins = make(map[string]string)
+	}
+	c.origins[key] = origin
 }
 
-// getDefaultDatabasePath returns the default database path for the current platform
-// Linux/Unix: ~/.config/bookmark-manager/bookmarks.db
-// macOS: ~/Library/Application Support/bookmark-manager/bookmarks.db
-// Windows: %APPDATA%/bookmark-manager/bookmarks.db
-func getDefaultDatabasePath() (string, error) {
+// Origin reports where a setting was set from: OriginDefault, OriginFile,
+// OriginFlag or "env" followed by the environment variable
+func (c *Config) Origin(key string) string {
+	if origin, ok := c.origins[key]; ok {
+		return origin
+	}
+	return OriginDefault
+}
+
+// Dir returns the application's configuration directory for the current platform
+// Linux/Unix: ~/.config/bookmark-manager (or $XDG_CONFIG_HOME/bookmark-manager)
+// macOS: ~/Library/Application Support/bookmark-manager
+// Windows: %APPDATA%/bookmark-manager
+func Dir() (string, error) {
 	var configDir string
-	var err error
 
 	switch runtime.GOOS {
 	case "windows":
@@ -72,15 +164,23 @@ func getDefaultDatabasePath() (string, error) {
 		}
 	}
 
-	appDir := filepath.Join(configDir, "bookmark-manager")
-	dbPath := filepath.Join(appDir, "bookmarks.db")
+	return filepath.Join(configDir, "bookmark-manager"), nil
+}
+
+// getDefaultDatabasePath returns the default database path, bookmarks.db in the
+// configuration directory, creating the directory when needed
+func getDefaultDatabasePath() (string, error) {
+	appDir, err := Dir()
+	if err != nil {
+		return "", err
+	}
 
 	// Ensure the directory exists
-	if err = os.MkdirAll(appDir, 0755); err != nil {
+	if err := os.MkdirAll(appDir, 0755); err != nil {
 		return "", fmt.Errorf("failed to create config directory %s: %w", appDir, err)
 	}
 
-	return dbPath, nil
+	return filepath.Join(appDir, "bookmarks.db"), nil
 }
 
 // GetDatabasePath returns the configured database path
@@ -92,3 +192,44 @@ func (c *Config) GetDatabasePath() string {
 func (c *Config) GetLogLevel() string {
 	return c.LogLevel
 }
+
+// GetDefaultCategory returns the category of bookmarks added without one
+func (c *Config) GetDefaultCategory() string {
+	return c.DefaultCategory
+}
+
+// GetSort returns the configured default sort order
+func (c *Config) GetSort() string {
+	return c.Sort
+}
+
+// GetHeight returns the configured default height of the TUI
+func (c *Config) GetHeight() string {
+	return c.Height
+}
+
+// GetOpener returns the configured command opening folders, empty for the
+// system file manager
+func (c *Config) GetOpener() []string {
+	return c.Opener
+}
+
+// GetTheme returns the configured color theme
+func (c *Config) GetTheme() string {
+	return c.Theme
+}
+
+// GetBackground returns the configured terminal background: auto, light or dark
+func (c *Config) GetBackground() string {
+	return c.Background
+}
+
+// GetThemes returns the user themes by name
+func (c *Config) GetThemes() map[string]Theme {
+	return c.Themes
+}
+
+// GetKeys returns the configured key bindings
+func (c *Config) GetKeys() KeyBindings {
+	return c.Keys
+}
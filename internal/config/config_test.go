@@ -42,6 +42,9 @@ func TestLoad(t *testing.T) {
 		},
 	}
 
+	// Keep a config file of the user out of the test
+	t.Setenv("BM_CONFIG", filepath.Join(t.TempDir(), "config.toml"))
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Set environment variables
@@ -117,3 +120,191 @@ func TestConfigGetters(t *testing.T) {
 		t.Errorf("GetLogLevel() = %v, want %v", cfg.GetLogLevel(), "debug")
 	}
 }
+
+func TestLoadPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	content := `database = "/file/bookmarks.db"
+sort = "frecency"
+height = "40%"
+opener = ["code", "--new-window"]
+
+[keys]
+preset = "vim"
+
+[keys.list]
+delete = ["D"]
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BM_CONFIG", path)
+	t.Setenv("BM_DATABASE", "")
+	t.Setenv("BM_LOGLEVEL", "")
+	t.Setenv("BM_SORT", "")
+	t.Setenv("BM_HEIGHT", "20")
+
+	Override("database", "database", "/flag/bookmarks.db")
+	t.Cleanup(func() { overrides = nil })
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	tests := []struct {
+		key, want, origin string
+	}{
+		{"database", "/flag/bookmarks.db", OriginFlag},
+		{"log_level", "warn", OriginDefault},
+		{"sort", "frecency", OriginFile},
+		{"height", "20", "env BM_HEIGHT"},
+		{"opener", "code --new-window", OriginFile},
+		{"keys.preset", "vim", OriginFile},
+		{"keys.list.delete", "D", OriginFile},
+	}
+	for _, tt := range tests {
+		got, err := Get(cfg, tt.key)
+		if err != nil {
+			t.Fatalf("Get(%q) error = %v", tt.key, err)
+		}
+		if got != tt.want {
+			t.Errorf("Get(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+		if origin := cfg.Origin(tt.key); origin != tt.origin {
+			t.Errorf("Origin(%q) = %q, want %q", tt.key, origin, tt.origin)
+		}
+	}
+}
+
+func TestLoadFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"valid", "sort = \"frecency\"\n[keys.list]\nquit = [\"q\"]\n", ""},
+		{"syntax", "sort = \"frecency\n", "line 1"},
+		{"unknown key", "srot = \"frecency\"\n", `did you mean "sort"`},
+		{"unknown table", "[colors]\nbg = 1\n", `unknown setting "colors"`},
+		{"bad value", "sort = \"newest\"\n", "sort: invalid sort order"},
+		{"bad height", "height = \"0\"\n", "invalid height"},
+		{"wrong type", "opener = 1\n", "incompatible types"},
+		{"keys not a table", "keys = \"x\"\n", "expected table"},
+		{"unknown key scope", "[keys.lists]\nquit = [\"q\"]\n", `did you mean "list"`},
+		{"valid theme", "theme = \"mine\"\n[themes.mine]\nbase = \"dracula\"\nprimary = \"#f0f\"\nmuted = 8\nsubtle = { light = \"#aaaaaa\", dark = \"#555555\" }\n", ""},
+		{"bad color", "[themes.mine]\nprimary = \"magenta\"\n", `invalid color "magenta"`},
+		{"bad ansi color", "[themes.mine]\nprimary = 256\n", `invalid color "256"`},
+		{"half color table", "[themes.mine]\nprimary = { dark = \"#fff\" }\n", "needs both light and dark"},
+		{"unknown color variant", "[themes.mine]\nprimary = { light = \"#fff\", dim = \"#000\" }\n", `unknown color variant "dim"`},
+		{"unknown theme color", "[themes.mine]\nprimay = \"#fff\"\n", `did you mean "primary"`},
+		{"bad background", "background = \"grey\"\n", "invalid background"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.toml")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := LoadFile(path)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("LoadFile() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadFile() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if cfg, err := LoadFile(filepath.Join(t.TempDir(), "missing.toml")); cfg != nil || err != nil {
+		t.Errorf("LoadFile() of a missing file = %v, %v; want nil, nil", cfg, err)
+	}
+}
+
+func TestSetAndSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "config.toml")
+	cfg := &Config{}
+
+	for key, value := range map[string]string{
+		"sort":             "frecency",
+		"opener":           `code "my folder"`,
+		"keys.list.delete": "x, d",
+	} {
+		if err := Set(cfg, key, value); err != nil {
+			t.Fatalf("Set(%q) error = %v", key, err)
+		}
+	}
+	if err := Set(cfg, "sort", "newest"); err == nil {
+		t.Error("Set() with an invalid sort order should fail")
+	}
+	if err := Save(path, cfg); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	saved, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if saved.Sort != "frecency" {
+		t.Errorf("saved sort = %q, want frecency", saved.Sort)
+	}
+	if strings.Join(saved.Opener, "|") != "code|my folder" {
+		t.Errorf("saved opener = %q, want [code, my folder]", saved.Opener)
+	}
+	if strings.Join(saved.Keys.List["delete"], "|") != "x|d" {
+		t.Errorf("saved delete keys = %q, want [x, d]", saved.Keys.List["delete"])
+	}
+
+	// Themes are kept when the file is rewritten
+	cfg.Themes = map[string]Theme{"mine": {
+		Base:    "solarized",
+		Primary: Color{Light: "#f0f", Dark: "#f0f"},
+		Muted:   Color{Light: "#aaaaaa", Dark: "#555555"},
+	}}
+	if err := Save(path, cfg); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	saved, err = LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if got := saved.Themes["mine"]; got != cfg.Themes["mine"] {
+		t.Errorf("saved theme = %+v, want %+v", got, cfg.Themes["mine"])
+	}
+
+	if err := Set(cfg, "keys.dialog.yes", "y"); err == nil {
+		t.Error("Set() with an unknown key binding scope should fail")
+	}
+}
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{in: "code -n", want: []string{"code", "-n"}},
+		{in: `open -a "Visual Studio Code"`, want: []string{"open", "-a", "Visual Studio Code"}},
+		{in: `echo 'it''s' a\ b`, want: []string{"echo", "its", "a b"}},
+		{in: `code "unterminated`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := splitCommand(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("splitCommand(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+		}
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("splitCommand(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if !tt.wantErr {
+			if again, _ := splitCommand(joinCommand(got)); strings.Join(again, "|") != strings.Join(got, "|") {
+				t.Errorf("joinCommand(%q) does not split back: %q", got, joinCommand(got))
+			}
+		}
+	}
+}
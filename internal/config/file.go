@@ -0,0 +1,153 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// FileName is the name of the config file in the configuration directory
+const FileName = "config.toml"
+
+// pathOverride is the config file given on the command line, see SetPath
+var pathOverride string
+
+// override is a setting given on the command line
+type override struct {
+	flag  string
+	key   string
+	value string
+}
+
+// overrides are the settings given on the command line, applied last by Load
+var overrides []override
+
+// SetPath sets the config file given on the command line, which takes
+// precedence over BM_CONFIG
+func SetPath(path string) {
+	pathOverride = path
+}
+
+// Override sets a setting from a command line flag, taking precedence over the
+// environment and the config file when the configuration is loaded
+func Override(flag, key, value string) {
+	overrides = append(overrides, override{flag: flag, key: key, value: value})
+}
+
+// Path returns the config file in use: the one given on the command line, then
+// BM_CONFIG, then config.toml in the configuration directory
+func Path() (string, error) {
+	if pathOverride != "" {
+		return pathOverride, nil
+	}
+	if path := os.Getenv("BM_CONFIG"); path != "" {
+		return path, nil
+	}
+
+	dir, err := Dir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return filepath.Join(dir, FileName), nil
+}
+
+// LoadFile reads and validates a config file on its own, without defaults or
+// environment variables. A missing file is not an error and returns nil.
+func LoadFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	config, err := parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// parse decodes and validates the contents of a config file
+func parse(data []byte) (*Config, error) {
+	config := &Config{}
+	meta, err := toml.Decode(string(data), config)
+	if err != nil {
+		var parseErr toml.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, fmt.Errorf("line %d: %s", parseErr.Position.Line, parseErr.Message)
+		}
+		return nil, err
+	}
+
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		key := undecoded[0].String()
+		return nil, unknownKeyError(key)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Save writes the configuration to a config file, creating its directory.
+// The file is rewritten as a whole, so comments are not preserved.
+func Save(path string, config *Config) error {
+	var buf bytes.Buffer
+	encoder := toml.NewEncoder(&buf)
+	encoder.Indent = ""
+	if err := encoder.Encode(config); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// Template returns the contents of a new config file documenting every
+// setting, all commented out
+func Template() string {
+	var b strings.Builder
+	b.WriteString("# bookmark-manager configuration\n")
+	b.WriteString("# Environment variables and command line flags override these settings.\n")
+	for _, s := range settings {
+		if strings.HasPrefix(s.key, KeysPrefix) {
+			continue
+		}
+		fmt.Fprintf(&b, "\n# %s (%s)\n# %s = %s\n", s.usage, s.env, s.key, s.example)
+	}
+
+	// Key bindings go in tables at the end of the file
+	preset, _ := lookup("keys.preset")
+	fmt.Fprintf(&b, "\n# Key bindings, replacing those of the preset per action\n# [keys]\n# %s (%s)\n# preset = %s\n",
+		preset.usage, preset.env, preset.example)
+	examples := map[string]string{
+		KeyScopeList:    `delete = ["x", "D"]`,
+		KeyScopeConfirm: `yes = ["y", "enter"]`,
+		KeyScopeEdit:    `cancel = ["esc", "ctrl+g"]`,
+	}
+	for _, scope := range KeyScopes {
+		fmt.Fprintf(&b, "#\n# [keys.%s]\n# %s\n", scope, examples[scope])
+	}
+
+	// As do the user themes
+	b.WriteString("\n# Themes selected with the theme setting, replacing colors of their base theme.\n")
+	fmt.Fprintf(&b, "# Colors: %s.\n", strings.Join(ThemeColors, ", "))
+	b.WriteString("# Each is \"#rrggbb\", \"#rgb\", an ANSI color number or { light = ..., dark = ... }.\n")
+	b.WriteString("# [themes.mine]\n# base = \"dracula\"\n# primary = \"#ff79c6\"\n")
+	b.WriteString("# muted = { light = \"#d0d0d0\", dark = \"#44475a\" }\n")
+	return b.String()
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Key binding scopes, one per TUI model
+const (
+	KeyScopeList    = "list"
+	KeyScopeConfirm = "confirm"
+	KeyScopeEdit    = "edit"
+)
+
+// KeyScopes lists the key binding scopes in display order
+var KeyScopes = []string{KeyScopeList, KeyScopeConfirm, KeyScopeEdit}
+
+// KeyBindings holds the [keys] table: a preset and, per scope, the keys bound
+// to actions, replacing those of the preset. Action names and presets are
+// defined and validated by the TUI.
+type KeyBindings struct {
+	Preset  string              `toml:"preset,omitempty"`
+	List    map[string][]string `toml:"list,omitempty"`
+	Confirm map[string][]string `toml:"confirm,omitempty"`
+	Edit    map[string][]string `toml:"edit,omitempty"`
+}
+
+// Scope returns the bindings of a scope by name, reporting false for unknown
+// scopes
+func (k *KeyBindings) Scope(name string) (map[string][]string, bool) {
+	bindings := k.scope(name)
+	if bindings == nil {
+		return nil, false
+	}
+	return *bindings, true
+}
+
+// scope returns the field holding the bindings of a scope, nil for unknown
+// scopes
+func (k *KeyBindings) scope(name string) *map[string][]string {
+	switch name {
+	case KeyScopeList:
+		return &k.List
+	case KeyScopeConfirm:
+		return &k.Confirm
+	case KeyScopeEdit:
+		return &k.Edit
+	}
+	return nil
+}
+
+// bind sets the keys of an action, removing the binding when keys is nil. An
+// empty list leaves the action unbound.
+func (k *KeyBindings) bind(scope, action string, keys []string) {
+	bindings := k.scope(scope)
+	if keys == nil {
+		delete(*bindings, action)
+		return
+	}
+	if *bindings == nil {
+		*bindings = make(map[string][]string)
+	}
+	(*bindings)[action] = keys
+}
+
+// parseBindingKey splits a setting such as "keys.list.delete" into its scope
+// and action
+func parseBindingKey(key string) (scope, action string, err error) {
+	rest, _ := strings.CutPrefix(key, KeysPrefix)
+	scope, action, _ = strings.Cut(rest, ".")
+	if (&KeyBindings{}).scope(scope) == nil {
+		if suggestion := closest(scope, KeyScopes); suggestion != "" {
+			return "", "", fmt.Errorf("unknown key binding scope %q in %q (did you mean %q?)", scope, key, suggestion)
+		}
+		return "", "", fmt.Errorf("unknown key binding scope %q in %q (expected %s%s.<action>)",
+			scope, key, KeysPrefix, strings.Join(KeyScopes, "|"))
+	}
+	if action == "" {
+		return "", "", fmt.Errorf("missing action in %q (expected %s%s.<action>)", key, KeysPrefix, scope)
+	}
+	return scope, action, nil
+}
+
+// parseKeyList splits a comma-separated list of keys
+func parseKeyList(value string) []string {
+	var keys []string
+	for _, k := range strings.Split(value, ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,397 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// KeysPrefix starts the names of key binding settings, as in "keys.list.delete"
+const KeysPrefix = "keys."
+
+// setting describes a scalar setting of the config file and its environment
+// variable. Values are exchanged as strings so that the file, the environment,
+// flags and the config command share the same parsing and validation.
+type setting struct {
+	key     string
+	env     string
+	usage   string
+	example string // TOML value shown in the config file template
+	get     func(c *Config) string
+	set     func(c *Config, value string) error
+}
+
+// logLevels are the log levels understood by the database logger
+var logLevels = []string{"silent", "error", "warn", "info"}
+
+// sortOrders are the sort orders understood by the bookmark service, which
+// cannot be imported here
+var sortOrders = []string{"category", "frecency"}
+
+// backgrounds are the terminal backgrounds; auto asks the terminal
+var backgrounds = []string{"auto", "light", "dark"}
+
+// settings lists every scalar setting in the order they are shown
+var settings = []setting{
+	{
+		key:     "database",
+		env:     "BM_DATABASE",
+		usage:   "Path of the SQLite database; a leading ~ is the home directory",
+		example: `"/path/to/bookmarks.db"`,
+		get:     func(c *Config) string { return c.DatabasePath },
+		set: func(c *Config, value string) error {
+			path, err := expandHome(strings.TrimSpace(value))
+			if err != nil {
+				return err
+			}
+			c.DatabasePath = path
+			return nil
+		},
+	},
+	{
+		key:     "log_level",
+		env:     "BM_LOGLEVEL",
+		usage:   "Database log level: " + strings.Join(logLevels, ", "),
+		example: `"warn"`,
+		get:     func(c *Config) string { return c.LogLevel },
+		set: func(c *Config, value string) error {
+			if err := oneOf("log level", value, logLevels); err != nil {
+				return err
+			}
+			c.LogLevel = value
+			return nil
+		},
+	},
+	{
+		key:     "default_category",
+		env:     "BM_CATEGORY",
+		usage:   "Category of bookmarks added without one",
+		example: `"inbox"`,
+		get:     func(c *Config) string { return c.DefaultCategory },
+		set: func(c *Config, value string) error {
+			c.DefaultCategory = strings.TrimSpace(value)
+			return nil
+		},
+	},
+	{
+		key:     "sort",
+		env:     "BM_SORT",
+		usage:   "Default sort order of list, pick and ls: " + strings.Join(sortOrders, ", "),
+		example: `"frecency"`,
+		get:     func(c *Config) string { return c.Sort },
+		set: func(c *Config, value string) error {
+			if err := oneOf("sort order", value, sortOrders); err != nil {
+				return err
+			}
+			c.Sort = value
+			return nil
+		},
+	},
+	{
+		key:     "height",
+		env:     "BM_HEIGHT",
+		usage:   "Inline height of the TUI in lines or percent, e.g. 40%; empty for fullscreen",
+		example: `"40%"`,
+		get:     func(c *Config) string { return c.Height },
+		set: func(c *Config, value string) error {
+			if err := validateHeight(value); err != nil {
+				return err
+			}
+			c.Height = strings.TrimSpace(value)
+			return nil
+		},
+	},
+	{
+		key:     "opener",
+		env:     "BM_OPENER",
+		usage:   "Command opening folders, e.g. \"code -n\"; {} is replaced by the folder, which is appended otherwise",
+		example: `["code", "--new-window"]`,
+		get:     func(c *Config) string { return joinCommand(c.Opener) },
+		set: func(c *Config, value string) error {
+			opener, err := splitCommand(value)
+			if err != nil {
+				return err
+			}
+			c.Opener = opener
+			return nil
+		},
+	},
+	{
+		key:     "keys.preset",
+		env:     "BM_KEYMAP",
+		usage:   "Key binding preset the [keys] tables are applied over: default, vim or emacs",
+		example: `"vim"`,
+		get:     func(c *Config) string { return c.Keys.Preset },
+		set: func(c *Config, value string) error {
+			c.Keys.Preset = strings.TrimSpace(value)
+			return nil
+		},
+	},
+	{
+		key:     "theme",
+		env:     "BM_THEME",
+		usage:   "Color theme: default, ansi, dracula, solarized, mono or a [themes.<name>] table",
+		example: `"solarized"`,
+		get:     func(c *Config) string { return c.Theme },
+		set: func(c *Config, value string) error {
+			c.Theme = strings.TrimSpace(value)
+			return nil
+		},
+	},
+	{
+		key:     "background",
+		env:     "BM_BACKGROUND",
+		usage:   "Terminal background the theme colors are chosen for: " + strings.Join(backgrounds, ", "),
+		example: `"light"`,
+		get:     func(c *Config) string { return c.Background },
+		set: func(c *Config, value string) error {
+			if err := oneOf("background", value, backgrounds); err != nil {
+				return err
+			}
+			c.Background = value
+			return nil
+		},
+	},
+}
+
+// Setting describes a setting for help and the config command
+type Setting struct {
+	Key   string
+	Env   string
+	Usage string
+}
+
+// Settings returns the scalar settings in display order. Key bindings are
+// set per scope and action with keys such as "keys.list.delete".
+func Settings() []Setting {
+	result := make([]Setting, len(settings))
+	for i, s := range settings {
+		result[i] = Setting{Key: s.key, Env: s.env, Usage: s.usage}
+	}
+	return result
+}
+
+// lookup finds a scalar setting by key
+func lookup(key string) (setting, bool) {
+	for _, s := range settings {
+		if s.key == key {
+			return s, true
+		}
+	}
+	return setting{}, false
+}
+
+// Get returns the value of a setting as a string. Key bindings are returned as
+// a comma-separated list.
+func Get(c *Config, key string) (string, error) {
+	if s, ok := lookup(key); ok {
+		return s.get(c), nil
+	}
+	if !strings.HasPrefix(key, KeysPrefix) {
+		return "", unknownKeyError(key)
+	}
+
+	scope, action, err := parseBindingKey(key)
+	if err != nil {
+		return "", err
+	}
+	bindings, _ := c.Keys.Scope(scope)
+	return strings.Join(bindings[action], ","), nil
+}
+
+// Set parses, validates and sets a setting from a string. Key bindings take a
+// comma-separated list of keys, and an empty value removes them.
+func Set(c *Config, key, value string) error {
+	if s, ok := lookup(key); ok {
+		return s.set(c, value)
+	}
+	if !strings.HasPrefix(key, KeysPrefix) {
+		return unknownKeyError(key)
+	}
+
+	scope, action, err := parseBindingKey(key)
+	if err != nil {
+		return err
+	}
+	c.Keys.bind(scope, action, parseKeyList(value))
+	return nil
+}
+
+// Validate checks every setting of the configuration
+func (c *Config) Validate() error {
+	for _, s := range settings {
+		if value := s.get(c); value != "" {
+			if err := s.set(&Config{}, value); err != nil {
+				return fmt.Errorf("%s: %w", s.key, err)
+			}
+		}
+	}
+	for _, scope := range KeyScopes {
+		bindings, _ := c.Keys.Scope(scope)
+		for action, keys := range bindings {
+			for _, k := range keys {
+				if strings.TrimSpace(k) == "" {
+					return fmt.Errorf("%s%s.%s: empty key", KeysPrefix, scope, action)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// unknownKeyError reports an unknown setting, suggesting the closest known one
+func unknownKeyError(key string) error {
+	if strings.HasPrefix(key, KeysPrefix) {
+		if _, _, err := parseBindingKey(key); err != nil {
+			return err
+		}
+	}
+	if strings.HasPrefix(key, ThemesPrefix) {
+		return themeKeyError(key)
+	}
+
+	known := make([]string, len(settings))
+	for i, s := range settings {
+		known[i] = s.key
+	}
+	if suggestion := closest(key, known); suggestion != "" {
+		return fmt.Errorf("unknown setting %q (did you mean %q?)", key, suggestion)
+	}
+	return fmt.Errorf("unknown setting %q (expected one of %s, or %s<scope>.<action>)",
+		key, strings.Join(known, ", "), KeysPrefix)
+}
+
+// closest returns the candidate within a small edit distance of name, if any
+func closest(name string, candidates []string) string {
+	best, bestDistance := "", 3
+	for _, candidate := range candidates {
+		if d := editDistance(name, candidate); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+// editDistance computes the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// expandHome replaces a leading ~ in a path with the user's home directory
+func expandHome(path string) (string, error) {
+	rest, ok := strings.CutPrefix(path, "~")
+	if !ok || (rest != "" && !os.IsPathSeparator(rest[0])) {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return home + rest, nil
+}
+
+// oneOf checks that value is one of the allowed values
+func oneOf(name, value string, allowed []string) error {
+	if !slices.Contains(allowed, value) {
+		return fmt.Errorf("invalid %s %q (expected one of %s)", name, value, strings.Join(allowed, ", "))
+	}
+	return nil
+}
+
+// validateHeight checks a TUI height, a number of lines or a percentage. It
+// mirrors the parsing done by the TUI, which cannot be imported here.
+func validateHeight(value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+
+	number, percent := strings.CutSuffix(value, "%")
+	n, err := strconv.Atoi(number)
+	if err != nil || n <= 0 || (percent && n > 100) {
+		return fmt.Errorf("invalid height %q (expected a number of lines or a percentage up to 100%%)", value)
+	}
+	return nil
+}
+
+// splitCommand splits a command line into words, honouring single and double
+// quotes and backslash escapes outside single quotes
+func splitCommand(line string) ([]string, error) {
+	var (
+		words   []string
+		word    strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inWord = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case unicode.IsSpace(r):
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in command %q", quote, line)
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash in command %q", line)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// joinCommand joins command words into a line splitCommand splits back,
+// quoting the words that need it
+func joinCommand(words []string) string {
+	quoted := make([]string, len(words))
+	for i, w := range words {
+		if w == "" || strings.ContainsAny(w, " \t\n'\"\\") {
+			w = "'" + strings.ReplaceAll(w, "'", `'\''`) + "'"
+		}
+		quoted[i] = w
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ThemesPrefix starts the names of theme settings, as in "themes.mine.primary"
+const ThemesPrefix = "themes."
+
+// Theme holds a [themes.<name>] table: a user theme whose colors replace those
+// of its base theme. Built-in themes are defined by the TUI.
+type Theme struct {
+	Base            string `toml:"base,omitempty"`             // Theme providing the colors not set, "default" when empty
+	Primary         Color  `toml:"primary,omitempty"`          // Selection, focused input and filter matches
+	Secondary       Color  `toml:"secondary,omitempty"`        // Selection border and description
+	Text            Color  `toml:"text,omitempty"`             // Bookmark folders
+	Subtle          Color  `toml:"subtle,omitempty"`           // Descriptions, status bar and help keys
+	Muted           Color  `toml:"muted,omitempty"`            // Borders, hints and help descriptions
+	Title           Color  `toml:"title,omitempty"`            // Title text
+	TitleBackground Color  `toml:"title_background,omitempty"` // Title background
+	Success         Color  `toml:"success,omitempty"`
+	Warning         Color  `toml:"warning,omitempty"`
+	Error           Color  `toml:"error,omitempty"`
+}
+
+// ThemeColors lists the colors of a theme table in display order
+var ThemeColors = []string{
+	"primary", "secondary", "text", "subtle", "muted",
+	"title", "title_background", "success", "warning", "error",
+}
+
+// Colors returns the colors of the theme by name, unset ones included
+func (t *Theme) Colors() map[string]Color {
+	return map[string]Color{
+		"primary":          t.Primary,
+		"secondary":        t.Secondary,
+		"text":             t.Text,
+		"subtle":           t.Subtle,
+		"muted":            t.Muted,
+		"title":            t.Title,
+		"title_background": t.TitleBackground,
+		"success":          t.Success,
+		"warning":          t.Warning,
+		"error":            t.Error,
+	}
+}
+
+// Color is a theme color: "#rrggbb", "#rgb" or an ANSI color number from 0 to
+// 255, either for any background or, written as a table such as
+// { light = "#5a56e0", dark = "#7d56f4" }, per terminal background
+type Color struct {
+	Light string
+	Dark  string
+}
+
+// IsZero reports whether the color is unset
+func (c Color) IsZero() bool {
+	return c.Light == "" && c.Dark == ""
+}
+
+// UnmarshalTOML implements toml.Unmarshaler
+func (c *Color) UnmarshalTOML(value any) error {
+	switch v := value.(type) {
+	case string:
+		if err := validateColor(v); err != nil {
+			return err
+		}
+		c.Light, c.Dark = v, v
+		return nil
+	case int64:
+		// An ANSI color number written without quotes
+		return c.UnmarshalTOML(strconv.FormatInt(v, 10))
+	case map[string]any:
+		for name, color := range v {
+			s, ok := color.(string)
+			if !ok {
+				return fmt.Errorf("invalid %s color: expected a string", name)
+			}
+			if err := validateColor(s); err != nil {
+				return err
+			}
+			switch name {
+			case "light":
+				c.Light = s
+			case "dark":
+				c.Dark = s
+			default:
+				return fmt.Errorf("unknown color variant %q (expected light or dark)", name)
+			}
+		}
+		if c.Light == "" || c.Dark == "" {
+			return fmt.Errorf("a color table needs both light and dark")
+		}
+		return nil
+	}
+	return fmt.Errorf("invalid color: expected a string or a table of light and dark colors")
+}
+
+// MarshalTOML implements toml.Marshaler, writing a single color when it does
+// not depend on the background
+func (c Color) MarshalTOML() ([]byte, error) {
+	if c.Light == c.Dark {
+		return []byte(strconv.Quote(c.Dark)), nil
+	}
+	return []byte(fmt.Sprintf("{ light = %s, dark = %s }", strconv.Quote(c.Light), strconv.Quote(c.Dark))), nil
+}
+
+// hexColor matches "#rgb" and "#rrggbb"
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// validateColor checks a color as understood by the terminal styling library
+func validateColor(value string) error {
+	if hexColor.MatchString(value) {
+		return nil
+	}
+	if n, err := strconv.Atoi(value); err == nil && n >= 0 && n <= 255 {
+		return nil
+	}
+	return fmt.Errorf("invalid color %q (expected #rrggbb, #rgb or an ANSI color number from 0 to 255)", value)
+}
+
+// themeKeyError reports an unknown key in a theme table, suggesting the
+// closest color
+func themeKeyError(key string) error {
+	rest, _ := strings.CutPrefix(key, ThemesPrefix)
+	name, field, _ := strings.Cut(rest, ".")
+	field, _, _ = strings.Cut(field, ".")
+	known := append([]string{"base"}, ThemeColors...)
+	if suggestion := closest(field, known); suggestion != "" {
+		return fmt.Errorf("unknown theme setting %q in theme %q (did you mean %q?)", field, name, suggestion)
+	}
+	return fmt.Errorf("unknown theme setting %q in theme %q (expected one of %s)", field, name, strings.Join(known, ", "))
+}
@@ -1,10 +1,18 @@
 // Package database provides SQLite3 database connectivity and ORM functionality
-// using GORM.
+// using GORM. The schema is managed by versioned SQL migrations embedded in the
+// binary.
 package database
 
 import (
 	"fmt"
-
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/term"
 	"github.com/jhoffmann/bookmark-manager/internal/config"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -16,6 +24,9 @@ type DB interface {
 	// Connection management
 	Close() error
 	Ping() error
+	// Schema management
+	Migrate() ([]Migration, error)
+	MigrationStatus() ([]MigrationStatus, error)
 	// Internal method for testing
 	GetDB() *gorm.DB
 }
@@ -26,7 +37,46 @@ type Database struct {
 }
 
 // NewDatabase creates a new database connection with the provided configuration
+// and applies any pending migrations
 func NewDatabase(cfg *config.Config) (DB, error) {
+	database, err := Open(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := database.migrate(); err != nil {
+		database.Close()
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	return database, nil
+}
+
+// Open creates a new database connection without applying migrations
+func Open(cfg *config.Config) (*Database, error) {
+	return open(cfg, cfg.GetDatabasePath())
+}
+
+// OpenReadOnly opens an existing database for reading only, without applying
+// migrations. Unlike Open, it never creates the database file.
+func OpenReadOnly(cfg *config.Config) (*Database, error) {
+	path := cfg.GetDatabasePath()
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// SQLite takes the mode from a file: URI, in which the path is escaped and
+	// Windows drive letters follow a slash
+	uriPath := filepath.ToSlash(path)
+	if filepath.IsAbs(path) && !strings.HasPrefix(uriPath, "/") {
+		uriPath = "/" + uriPath
+	}
+	dsn := &url.URL{Scheme: "file", Path: uriPath, RawQuery: "mode=ro"}
+	return open(cfg, dsn.String())
+}
+
+// open connects to the database named by the SQLite data source name
+func open(cfg *config.Config, dsn string) (*Database, error) {
 	// Configure GORM logger level
 	logLevel := logger.Warn
 	switch cfg.GetLogLevel() {
@@ -38,46 +88,30 @@ func NewDatabase(cfg *config.Config) (DB, error) {
 		logLevel = logger.Silent
 	}
 
-	db, err := gorm.Open(sqlite.Open(cfg.GetDatabasePath()), &gorm.Config{
-		Logger: logger.Default.LogMode(logLevel),
+	// Lookups that find nothing are expected (e.g. checking whether a folder is
+	// already bookmarked), so they are not logged as errors. The log goes to
+	// stderr, as stdout carries the output of commands like pick and jump.
+	dbLogger := logger.New(log.New(os.Stderr, "\r\n", log.LstdFlags), logger.Config{
+		SlowThreshold:             200 * time.Millisecond,
+		LogLevel:                  logLevel,
+		IgnoreRecordNotFoundError: true,
+		Colorful:                  term.IsTerminal(os.Stderr.Fd()),
+	})
+
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+		Logger: dbLogger,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	database := &Database{db: db}
-
-	if err := database.migrate(); err != nil {
-		return nil, fmt.Errorf("failed to migrate database: %w", err)
-	}
-
-	return database, nil
+	return &Database{db: db}, nil
 }
 
-// migrate runs auto-migration for all models
+// migrate applies all pending schema migrations
 func (d *Database) migrate() error {
-	// Auto-migrate bookmark model
-	if err := d.db.AutoMigrate(&BookmarkModel{}); err != nil {
-		return fmt.Errorf("failed to auto-migrate bookmark table: %w", err)
-	}
-	return nil
-}
-
-// BookmarkModel represents the bookmark table structure for migration
-// This is a minimal model definition for auto-migration purposes
-type BookmarkModel struct {
-	ID          uint    `gorm:"primaryKey"`
-	Folder      string  `gorm:"not null"`
-	DateCreated string  `gorm:"type:datetime"`
-	Category    string  `gorm:"type:varchar(50)"`
-	CreatedAt   string  `gorm:"type:datetime"`
-	UpdatedAt   string  `gorm:"type:datetime"`
-	DeletedAt   *string `gorm:"index;type:datetime"`
-}
-
-// TableName specifies the table name for the bookmark model
-func (BookmarkModel) TableName() string {
-	return "bookmarks"
+	_, err := d.Migrate()
+	return err
 }
 
 // Close closes the database connection
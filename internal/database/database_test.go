@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"github.com/jhoffmann/bookmark-manager/internal/config"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
 )
 
 func TestNewDatabase(t *testing.T) {
@@ -230,3 +232,43 @@ func TestDatabase_Integration(t *testing.T) {
 		t.Errorf("Close() error = %v", err)
 	}
 }
+
+func TestDatabase_MigrateCategoriesToTags(t *testing.T) {
+	tempDir := t.TempDir()
+	dbPath := filepath.Join(tempDir, "test_legacy.db")
+
+	// Create a database with the original single-category schema
+	legacy, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open legacy database: %v", err)
+	}
+	if err := legacy.Exec(`CREATE TABLE bookmarks (
+		id integer PRIMARY KEY AUTOINCREMENT, folder text NOT NULL,
+		date_created datetime, category varchar(50),
+		created_at datetime, updated_at datetime, deleted_at datetime)`).Error; err != nil {
+		t.Fatalf("Failed to create legacy table: %v", err)
+	}
+	if err := legacy.Exec(`INSERT INTO bookmarks (folder, category) VALUES
+		('/a', 'work'), ('/b', 'work'), ('/c', '')`).Error; err != nil {
+		t.Fatalf("Failed to insert legacy rows: %v", err)
+	}
+	sqlDB, _ := legacy.DB()
+	sqlDB.Close()
+
+	db, err := NewDatabase(&config.Config{DatabasePath: dbPath, LogLevel: "silent"})
+	if err != nil {
+		t.Fatalf("NewDatabase() error = %v", err)
+	}
+	defer db.Close()
+
+	var tags, links int64
+	db.GetDB().Table("tags").Count(&tags)
+	db.GetDB().Table("bookmark_tags").Count(&links)
+
+	if tags != 1 {
+		t.Errorf("Expected 1 tag after migration, got %d", tags)
+	}
+	if links != 2 {
+		t.Errorf("Expected 2 bookmark tags after migration, got %d", links)
+	}
+}
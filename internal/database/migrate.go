@@ -0,0 +1,242 @@
+package database
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// schemaVersionTable records which migrations have been applied
+const schemaVersionTable = "schema_version"
+
+// ErrNotVersioned is returned by MigrationStatus for databases without a
+// schema_version table: new ones, or ones created before versioning, whose
+// schema Migrate records before applying newer migrations
+var ErrNotVersioned = errors.New("no schema_version table")
+
+// Migration is a single versioned schema change embedded in the binary
+type Migration struct {
+	Version int
+	Name    string
+	SQL     string
+}
+
+// MigrationStatus describes whether a migration has been applied
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// schemaVersion represents a row of the schema_version table
+type schemaVersion struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// TableName specifies the table name for the schema version model
+func (schemaVersion) TableName() string {
+	return schemaVersionTable
+}
+
+// loadMigrations reads the embedded migrations, ordered by version. Files are
+// named NNNN_description.sql.
+func loadMigrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded migrations: %w", err)
+	}
+
+	migrations := make([]Migration, 0, len(entries))
+	seen := make(map[int]string, len(entries))
+
+	for _, entry := range entries {
+		filename := entry.Name()
+		prefix, name, ok := strings.Cut(strings.TrimSuffix(filename, ".sql"), "_")
+		if !ok {
+			return nil, fmt.Errorf("invalid migration filename %q", filename)
+		}
+
+		version, err := strconv.Atoi(prefix)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration version in %q", filename)
+		}
+		if other, dup := seen[version]; dup {
+			return nil, fmt.Errorf("duplicate migration version %d in %q and %q", version, other, filename)
+		}
+		seen[version] = filename
+
+		sql, err := migrationFiles.ReadFile("migrations/" + filename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %q: %w", filename, err)
+		}
+
+		migrations = append(migrations, Migration{
+			Version: version,
+			Name:    name,
+			SQL:     string(sql),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Migrate applies all pending migrations in order, each in its own transaction,
+// and returns the ones that were applied
+func (d *Database) Migrate() ([]Migration, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.ensureSchemaVersionTable(migrations); err != nil {
+		return nil, err
+	}
+
+	applied, err := d.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkKnownVersions(applied, migrations); err != nil {
+		return nil, err
+	}
+
+	var ran []Migration
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+
+		err := d.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(m.SQL).Error; err != nil {
+				return err
+			}
+			return tx.Create(&schemaVersion{
+				Version:   m.Version,
+				Name:      m.Name,
+				AppliedAt: time.Now(),
+			}).Error
+		})
+		if err != nil {
+			return ran, fmt.Errorf("failed to apply migration %04d_%s: %w", m.Version, m.Name, err)
+		}
+		ran = append(ran, m)
+	}
+
+	return ran, nil
+}
+
+// MigrationStatus lists every known migration along with when it was applied.
+// It only reads the database, and returns ErrNotVersioned when no migration
+// was ever recorded.
+func (d *Database) MigrationStatus() ([]MigrationStatus, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	if !d.db.Migrator().HasTable(schemaVersionTable) {
+		return nil, ErrNotVersioned
+	}
+
+	applied, err := d.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkKnownVersions(applied, migrations); err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, len(migrations))
+	for i, m := range migrations {
+		statuses[i] = MigrationStatus{Migration: m}
+		if appliedAt, ok := applied[m.Version]; ok {
+			statuses[i].AppliedAt = &appliedAt
+		}
+	}
+
+	return statuses, nil
+}
+
+// ensureSchemaVersionTable creates the schema_version table. Databases created
+// before versioning existed are stamped with the migrations their schema
+// already contains, so only newer migrations run against them.
+func (d *Database) ensureSchemaVersionTable(migrations []Migration) error {
+	migrator := d.db.Migrator()
+	if migrator.HasTable(schemaVersionTable) {
+		return nil
+	}
+
+	baseline := 0
+	if migrator.HasTable("bookmarks") {
+		baseline = 1
+		if migrator.HasColumn("bookmarks", "visit_count") {
+			baseline = 2
+		}
+		if migrator.HasTable("tags") {
+			baseline = 3
+		}
+	}
+
+	return d.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Migrator().CreateTable(&schemaVersion{}); err != nil {
+			return fmt.Errorf("failed to create schema version table: %w", err)
+		}
+
+		now := time.Now()
+		for _, m := range migrations {
+			if m.Version > baseline {
+				break
+			}
+			if err := tx.Create(&schemaVersion{Version: m.Version, Name: m.Name, AppliedAt: now}).Error; err != nil {
+				return fmt.Errorf("failed to record existing schema version: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// appliedVersions returns the applied migration versions and when they ran
+func (d *Database) appliedVersions() (map[int]time.Time, error) {
+	var rows []schemaVersion
+	if err := d.db.Order("version").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	applied := make(map[int]time.Time, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row.AppliedAt
+	}
+	return applied, nil
+}
+
+// checkKnownVersions refuses to touch databases migrated by a newer binary
+func checkKnownVersions(applied map[int]time.Time, migrations []Migration) error {
+	latest := 0
+	if len(migrations) > 0 {
+		latest = migrations[len(migrations)-1].Version
+	}
+
+	for version := range applied {
+		if version > latest {
+			return fmt.Errorf("database schema version %d is newer than this version of bookmark-manager supports (%d)", version, latest)
+		}
+	}
+	return nil
+}
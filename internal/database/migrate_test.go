@@ -0,0 +1,156 @@
+package database
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jhoffmann/bookmark-manager/internal/config"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatalf("loadMigrations() error = %v", err)
+	}
+
+	if len(migrations) == 0 {
+		t.Fatal("loadMigrations() returned no migrations")
+	}
+
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			t.Errorf("Expected migration %d to have version %d, got %d", i, i+1, m.Version)
+		}
+		if m.Name == "" || m.SQL == "" {
+			t.Errorf("Migration %d has empty name or SQL", m.Version)
+		}
+	}
+}
+
+func TestDatabase_MigrationStatus(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test_status.db")
+	cfg := &config.Config{DatabasePath: dbPath, LogLevel: "silent"}
+
+	// A database that has only been opened is reported, not versioned
+	opened, err := Open(cfg)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	if _, err := opened.MigrationStatus(); !errors.Is(err, ErrNotVersioned) {
+		t.Fatalf("MigrationStatus() error = %v, want ErrNotVersioned", err)
+	}
+	if opened.GetDB().Migrator().HasTable(schemaVersionTable) {
+		t.Fatal("Expected MigrationStatus() not to create the schema_version table")
+	}
+
+	migrations, _ := loadMigrations()
+	applied, err := opened.Migrate()
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if len(applied) != len(migrations) {
+		t.Errorf("Expected %d migrations applied, got %d", len(migrations), len(applied))
+	}
+
+	// Migrating again is a no-op
+	applied, err = opened.Migrate()
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if len(applied) != 0 {
+		t.Errorf("Expected no migrations on second run, got %d", len(applied))
+	}
+
+	statuses, err := opened.MigrationStatus()
+	if err != nil {
+		t.Fatalf("MigrationStatus() error = %v", err)
+	}
+	for _, s := range statuses {
+		if s.AppliedAt == nil {
+			t.Errorf("Expected migration %d to be applied", s.Version)
+		}
+	}
+	opened.Close()
+}
+
+func TestDatabase_MigrateAdoptsUnversionedSchema(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test_adopt.db")
+	cfg := &config.Config{DatabasePath: dbPath, LogLevel: "silent"}
+
+	// Simulate a database created before versioning, with visit tracking
+	opened, err := Open(cfg)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	migrations, _ := loadMigrations()
+	for _, m := range migrations[:2] {
+		if err := opened.GetDB().Exec(m.SQL).Error; err != nil {
+			t.Fatalf("Failed to apply migration %d: %v", m.Version, err)
+		}
+	}
+
+	applied, err := opened.Migrate()
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if len(applied) != len(migrations)-2 || applied[0].Version != 3 {
+		t.Errorf("Expected migrations from version 3 to be applied, got %v", applied)
+	}
+	opened.Close()
+}
+
+func TestDatabase_MigrateRejectsNewerSchema(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test_newer.db")
+	cfg := &config.Config{DatabasePath: dbPath, LogLevel: "silent"}
+
+	db, err := NewDatabase(cfg)
+	if err != nil {
+		t.Fatalf("NewDatabase() error = %v", err)
+	}
+	if err := db.GetDB().Create(&schemaVersion{Version: 9999, Name: "future", AppliedAt: time.Now()}).Error; err != nil {
+		t.Fatalf("Failed to record future version: %v", err)
+	}
+	db.Close()
+
+	db, err = NewDatabase(cfg)
+	if err == nil {
+		db.Close()
+		t.Fatal("NewDatabase() expected error for newer schema version, got nil")
+	}
+}
+
+func TestOpenReadOnly(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test_readonly.db")
+	cfg := &config.Config{DatabasePath: dbPath, LogLevel: "silent"}
+
+	// A missing database is not created
+	if _, err := OpenReadOnly(cfg); err == nil {
+		t.Fatal("OpenReadOnly() expected error for a missing database")
+	}
+	if _, err := os.Stat(dbPath); !os.IsNotExist(err) {
+		t.Fatalf("Expected OpenReadOnly() not to create the database, got %v", err)
+	}
+
+	db, err := NewDatabase(cfg)
+	if err != nil {
+		t.Fatalf("NewDatabase() error = %v", err)
+	}
+	db.Close()
+
+	readOnly, err := OpenReadOnly(cfg)
+	if err != nil {
+		t.Fatalf("OpenReadOnly() error = %v", err)
+	}
+	defer readOnly.Close()
+
+	if _, err := readOnly.MigrationStatus(); err != nil {
+		t.Errorf("MigrationStatus() error = %v", err)
+	}
+	if err := readOnly.GetDB().Exec("DELETE FROM schema_version").Error; err == nil {
+		t.Error("Expected writes to a read-only database to fail")
+	}
+}
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS bookmarks (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    folder text NOT NULL,
+    date_created datetime,
+    category varchar(50),
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime
+);
+
+CREATE INDEX IF NOT EXISTS idx_bookmarks_deleted_at ON bookmarks (deleted_at);
@@ -0,0 +1,3 @@
+ALTER TABLE bookmarks ADD COLUMN visit_count integer NOT NULL DEFAULT 0;
+
+ALTER TABLE bookmarks ADD COLUMN last_visited datetime;
@@ -0,0 +1,21 @@
+CREATE TABLE tags (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    name varchar(50) NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_tags_name ON tags (name);
+
+CREATE TABLE bookmark_tags (
+    bookmark_id integer NOT NULL,
+    tag_id integer NOT NULL,
+    PRIMARY KEY (bookmark_id, tag_id)
+);
+
+-- Every existing category becomes a tag on its bookmarks
+INSERT OR IGNORE INTO tags (name)
+SELECT DISTINCT category FROM bookmarks
+WHERE category IS NOT NULL AND category != '';
+
+INSERT OR IGNORE INTO bookmark_tags (bookmark_id, tag_id)
+SELECT bookmarks.id, tags.id FROM bookmarks
+JOIN tags ON tags.name = bookmarks.category;
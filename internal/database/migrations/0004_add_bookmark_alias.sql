@@ -0,0 +1,5 @@
+ALTER TABLE bookmarks ADD COLUMN alias varchar(50) NOT NULL DEFAULT '';
+
+-- Aliases are optional but unique among bookmarks that are not in the trash
+CREATE UNIQUE INDEX idx_bookmarks_alias ON bookmarks (alias)
+WHERE alias != '' AND deleted_at IS NULL;
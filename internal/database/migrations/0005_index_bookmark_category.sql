@@ -0,0 +1,2 @@
+-- Listing and completing categories is frequent enough to warrant an index
+CREATE INDEX idx_bookmarks_category ON bookmarks (category);
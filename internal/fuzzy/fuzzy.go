@@ -0,0 +1,144 @@
+// Package fuzzy implements subsequence matching of short patterns against
+// paths, scoring matches that start path segments and words higher so that
+// e.g. "bmgr" finds "~/src/bookmark-manager".
+package fuzzy
+
+import (
+	"unicode"
+)
+
+// Scoring weights. A matched character is worth scoreMatch plus the bonus of
+// its position; runs of consecutive characters earn scoreConsecutive per
+// character and gaps between matched characters cost penaltyGap per skipped
+// character, up to penaltyGapMax.
+const (
+	scoreMatch       = 16
+	scoreConsecutive = 8
+	bonusBoundary    = 10 // Start of the text or of a path segment
+	bonusWord        = 8  // After '-', '_', '.' or a space
+	bonusCamel       = 6  // Upper-case letter following a lower-case one
+	bonusBasename    = 4  // Any character in the last path segment
+	penaltyGap       = 1
+	penaltyGapMax    = 8
+)
+
+// Result is a successful match
+type Result struct {
+	Score int
+	// Positions are the indexes of the matched runes in the text, ascending
+	Positions []int
+}
+
+// Match reports whether every rune of pattern appears in text in order,
+// ignoring case, and returns the best-scoring alignment. An empty pattern
+// matches everything with a score of zero.
+func Match(pattern, text string) (Result, bool) {
+	p := []rune(pattern)
+	t := []rune(text)
+	if len(p) == 0 {
+		return Result{}, true
+	}
+	if len(p) > len(t) {
+		return Result{}, false
+	}
+
+	for i := range p {
+		p[i] = unicode.ToLower(p[i])
+	}
+
+	bonus := positionBonuses(t)
+	lower := make([]rune, len(t))
+	for i, r := range t {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	// best[i][j] is the best score of matching p[:i+1] with p[i] at t[j];
+	// from[i][j] is where p[i-1] was matched in that alignment
+	const none = -1 << 30
+	best := make([][]int, len(p))
+	from := make([][]int, len(p))
+	for i := range p {
+		best[i] = make([]int, len(t))
+		from[i] = make([]int, len(t))
+		for j := range t {
+			best[i][j] = none
+		}
+	}
+
+	for i := range p {
+		for j := i; j < len(t); j++ {
+			if lower[j] != p[i] {
+				continue
+			}
+
+			charScore := scoreMatch + bonus[j]
+			if i == 0 {
+				best[i][j] = charScore
+				continue
+			}
+
+			for k := i - 1; k < j; k++ {
+				if best[i-1][k] == none {
+					continue
+				}
+				score := best[i-1][k] + charScore
+				if k == j-1 {
+					score += scoreConsecutive
+				} else {
+					score -= min(penaltyGap*(j-k-1), penaltyGapMax)
+				}
+				if score > best[i][j] {
+					best[i][j] = score
+					from[i][j] = k
+				}
+			}
+		}
+	}
+
+	last := len(p) - 1
+	end := -1
+	for j := range t {
+		if best[last][j] != none && (end < 0 || best[last][j] > best[last][end]) {
+			end = j
+		}
+	}
+	if end < 0 {
+		return Result{}, false
+	}
+
+	positions := make([]int, len(p))
+	for i, j := last, end; i >= 0; i-- {
+		positions[i] = j
+		j = from[i][j]
+	}
+
+	return Result{Score: best[last][end], Positions: positions}, true
+}
+
+// positionBonuses returns the bonus for matching each rune of the text
+func positionBonuses(t []rune) []int {
+	basename := 0
+	for i, r := range t {
+		if r == '/' && i < len(t)-1 {
+			basename = i + 1
+		}
+	}
+
+	bonus := make([]int, len(t))
+	for i, r := range t {
+		switch {
+		case i == 0:
+			bonus[i] = bonusBoundary
+		case t[i-1] == '/' || t[i-1] == '\\':
+			bonus[i] = bonusBoundary
+		case t[i-1] == '-' || t[i-1] == '_' || t[i-1] == '.' || t[i-1] == ' ':
+			bonus[i] = bonusWord
+		case unicode.IsUpper(r) && unicode.IsLower(t[i-1]):
+			bonus[i] = bonusCamel
+		}
+		if i >= basename {
+			bonus[i] += bonusBasename
+		}
+	}
+	return bonus
+}
@@ -0,0 +1,60 @@
+package fuzzy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		text      string
+		ok        bool
+		positions []int
+	}{
+		{"", "/anything", true, nil},
+		{"bmgr", "/home/me/src/bookmark-manager", true, []int{13, 22, 26, 28}},
+		{"BM", "/home/me/src/bookmark-manager", true, []int{13, 22}},
+		{"api", "/work/api", true, []int{6, 7, 8}},
+		{"xyz", "/work/api", false, nil},
+		{"apix", "/work/api", false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" in "+tt.text, func(t *testing.T) {
+			got, ok := Match(tt.pattern, tt.text)
+			if ok != tt.ok {
+				t.Fatalf("Match() ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && !reflect.DeepEqual(got.Positions, tt.positions) {
+				t.Errorf("Match() positions = %v, want %v", got.Positions, tt.positions)
+			}
+		})
+	}
+}
+
+func TestMatchRanking(t *testing.T) {
+	score := func(pattern, text string) int {
+		t.Helper()
+		r, ok := Match(pattern, text)
+		if !ok {
+			t.Fatalf("Match(%q, %q) did not match", pattern, text)
+		}
+		return r.Score
+	}
+
+	// Basename matches beat matches in parent directories
+	if a, b := score("api", "/src/api"), score("api", "/api/src"); a <= b {
+		t.Errorf("basename score %d should beat parent score %d", a, b)
+	}
+
+	// Segment and word boundaries beat matches scattered inside words
+	if a, b := score("bm", "/src/bookmark-manager"), score("bm", "/src/submarine"); a <= b {
+		t.Errorf("boundary score %d should beat scattered score %d", a, b)
+	}
+
+	// Consecutive characters beat spread-out ones
+	if a, b := score("note", "/notes"), score("note", "/n-o-t-e"); a <= b {
+		t.Errorf("consecutive score %d should beat spread score %d", a, b)
+	}
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// AliasPrefix marks a bookmark reference by alias, as in "@api/cmd"
+const AliasPrefix = "@"
+
+// maxAliasLength matches the size of the alias column
+const maxAliasLength = 50
+
+// NormalizeAlias trims whitespace and a leading AliasPrefix from an alias
+func NormalizeAlias(alias string) string {
+	return strings.TrimPrefix(strings.TrimSpace(alias), AliasPrefix)
+}
+
+// ValidateAlias checks that an alias can be used in references: it must start
+// with a letter or digit, contain only letters, digits, '-', '_' and '.', and
+// not be a number, which would be mistaken for a bookmark ID
+func ValidateAlias(alias string) error {
+	if alias == "" {
+		return fmt.Errorf("alias is empty")
+	}
+	if len(alias) > maxAliasLength {
+		return fmt.Errorf("alias %q is longer than %d characters", alias, maxAliasLength)
+	}
+
+	digits := true
+	for i, r := range alias {
+		switch {
+		case unicode.IsDigit(r):
+		case unicode.IsLetter(r):
+			digits = false
+		case i > 0 && (r == '-' || r == '_' || r == '.'):
+			digits = false
+		default:
+			return fmt.Errorf("invalid alias %q: only letters, digits, '-', '_' and '.' are allowed, starting with a letter or digit", alias)
+		}
+	}
+	if digits {
+		return fmt.Errorf("invalid alias %q: an alias cannot be a number", alias)
+	}
+
+	return nil
+}
+
+// ParseAliasRef splits a reference such as "@api/cmd/server" into the alias
+// and the path below the aliased folder. It reports false when the reference
+// does not start with AliasPrefix.
+func ParseAliasRef(ref string) (alias, subPath string, ok bool) {
+	rest, ok := strings.CutPrefix(ref, AliasPrefix)
+	if !ok {
+		return "", "", false
+	}
+
+	if i := strings.IndexAny(rest, `/\`); i >= 0 {
+		return rest[:i], strings.TrimLeft(rest[i:], `/\`), true
+	}
+	return rest, "", true
+}
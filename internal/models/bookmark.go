@@ -11,12 +11,19 @@ import (
 // CategoryType represents the category of a bookmark
 type CategoryType string
 
-// Bookmark represents a folder bookmark entry
+// Bookmark represents a folder bookmark entry. Its tags are its categories:
+// listing, counting, filtering and renaming categories all go by Tags.
+// Category only names the primary one, which is shown and sorted by first,
+// and is always included in Tags when set.
 type Bookmark struct {
 	ID          uint           `gorm:"primaryKey" json:"id"`
 	Folder      string         `gorm:"not null" json:"folder"`
+	Alias       string         `gorm:"type:varchar(50);not null;default:''" json:"alias,omitempty"`
 	DateCreated time.Time      `json:"date_created"`
 	Category    CategoryType   `gorm:"type:varchar(50)" json:"category"`
+	VisitCount  int            `gorm:"not null;default:0" json:"visit_count"`
+	LastVisited *time.Time     `json:"last_visited,omitempty"`
+	Tags        []Tag          `gorm:"many2many:bookmark_tags" json:"tags,omitempty"`
 	CreatedAt   time.Time      `json:"-"`
 	UpdatedAt   time.Time      `json:"-"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
@@ -37,13 +44,77 @@ func (b *Bookmark) String() string {
 		b.ID, b.Folder, b.Category, b.DateCreated.Format("2006-01-02 15:04:05"))
 }
 
+// TagNames returns the names of the tags attached to the bookmark
+func (b *Bookmark) TagNames() []string {
+	names := make([]string, len(b.Tags))
+	for i, t := range b.Tags {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// HasTag reports whether the bookmark carries the named tag
+func (b *Bookmark) HasTag(name string) bool {
+	for _, t := range b.Tags {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// SetTagNames replaces the bookmark's tags with the given names. The tags are
+// resolved to database rows when the bookmark is saved.
+func (b *Bookmark) SetTagNames(names []string) {
+	names = NormalizeTagNames(names)
+	b.Tags = make([]Tag, len(names))
+	for i, name := range names {
+		b.Tags[i] = Tag{Name: name}
+	}
+}
+
+// Frecency returns a score combining how often and how recently the bookmark
+// was visited. Recent visits weigh more, so folders used daily outrank ones
+// visited many times long ago.
+func (b *Bookmark) Frecency(now time.Time) float64 {
+	if b.VisitCount == 0 || b.LastVisited == nil {
+		return 0
+	}
+
+	age := now.Sub(*b.LastVisited)
+	switch {
+	case age < time.Hour:
+		return float64(b.VisitCount) * 4
+	case age < 24*time.Hour:
+		return float64(b.VisitCount) * 2
+	case age < 7*24*time.Hour:
+		return float64(b.VisitCount) * 0.5
+	default:
+		return float64(b.VisitCount) * 0.25
+	}
+}
+
 // Validate performs validation on the bookmark fields
 func (b *Bookmark) Validate() error {
 	if b.Folder == "" {
 		return fmt.Errorf("folder path is required")
 	}
 
+	if b.Alias != "" {
+		if err := ValidateAlias(b.Alias); err != nil {
+			return err
+		}
+	}
+
 	// Allow empty category - no default assignment
+	if err := ValidateTagName(string(b.Category)); err != nil {
+		return fmt.Errorf("invalid category: %w", err)
+	}
+	for _, tag := range b.Tags {
+		if err := ValidateTagName(tag.Name); err != nil {
+			return fmt.Errorf("invalid tag: %w", err)
+		}
+	}
 
 	return nil
 }
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxTagLength matches the size of the tag name and category columns
+const maxTagLength = 50
+
+// ValidateTagName checks that a tag or category name fits its column
+func ValidateTagName(name string) error {
+	if len(name) > maxTagLength {
+		return fmt.Errorf("%q is longer than %d characters", name, maxTagLength)
+	}
+	return nil
+}
+
+// Tag represents a label that can be attached to any number of bookmarks
+type Tag struct {
+	ID   uint   `gorm:"primaryKey" json:"-"`
+	Name string `gorm:"type:varchar(50);uniqueIndex;not null" json:"name"`
+}
+
+// NormalizeTagNames trims tag names and drops empty and duplicate entries,
+// preserving the original order
+func NormalizeTagNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	normalized := make([]string, 0, len(names))
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		normalized = append(normalized, name)
+	}
+
+	return normalized
+}
@@ -0,0 +1,196 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// token is a lexical element of a query
+type token struct {
+	text string
+	// quoteAt is the byte offset in text where quoted content starts, or -1.
+	// Quoted tokens are never operators, and quoted colons do not start a field.
+	quoteAt int
+}
+
+func (t token) quoted() bool {
+	return t.quoteAt >= 0
+}
+
+// tokenize splits a query into words, parentheses and quoted strings
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	var current strings.Builder
+	inWord, inQuotes, quoteAt := false, false, -1
+
+	flush := func() {
+		if inWord {
+			tokens = append(tokens, token{text: current.String(), quoteAt: quoteAt})
+		}
+		current.Reset()
+		inWord, quoteAt = false, -1
+	}
+
+	for _, r := range input {
+		switch {
+		case inQuotes:
+			if r == '"' {
+				inQuotes = false
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"':
+			inQuotes, inWord = true, true
+			if quoteAt < 0 {
+				quoteAt = current.Len()
+			}
+		case unicode.IsSpace(r):
+			flush()
+		case r == '(' || r == ')':
+			flush()
+			tokens = append(tokens, token{text: string(r), quoteAt: -1})
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if inQuotes {
+		return nil, fmt.Errorf("unterminated quote in query")
+	}
+	flush()
+
+	return tokens, nil
+}
+
+// parser is a recursive descent parser over the tokens of a query:
+//
+//	or   = and { "OR" and }
+//	and  = not { ["AND"] not }
+//	not  = ("NOT" | "-") not | "(" or ")" | term
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+// accept consumes the next token if it is the given unquoted operator
+func (p *parser) accept(op string) bool {
+	if !p.done() && !p.peek().quoted() && p.peek().text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if p.accept("AND") {
+			// Explicit AND, the operand is mandatory
+		} else if p.done() || p.atOperator("OR") || p.atOperator(")") {
+			return left, nil
+		}
+
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left: left, right: right}
+	}
+}
+
+// atOperator reports whether the next token is the given unquoted operator
+func (p *parser) atOperator(op string) bool {
+	return !p.done() && !p.peek().quoted() && p.peek().text == op
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.done() {
+		return nil, fmt.Errorf("query ends unexpectedly")
+	}
+
+	if p.accept("NOT") {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand: operand}, nil
+	}
+
+	if p.accept("(") {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("missing closing parenthesis in query")
+		}
+		return inner, nil
+	}
+
+	tok := p.peek()
+	if !tok.quoted() {
+		switch tok.text {
+		case ")", "OR", "AND":
+			return nil, fmt.Errorf("unexpected %q in query", tok.text)
+		}
+	}
+	p.pos++
+
+	text, literalFrom := tok.text, tok.quoteAt
+	if literalFrom < 0 {
+		literalFrom = len(text)
+	}
+
+	negate := false
+	if literalFrom > 0 && len(text) > 1 && text[0] == '-' {
+		negate = true
+		text = text[1:]
+		literalFrom--
+	}
+
+	// A field name ends at the first colon outside quotes
+	field, value := "", text
+	if colon := strings.IndexByte(text, ':'); colon >= 0 && colon < literalFrom {
+		field, value = text[:colon], text[colon+1:]
+		if value == "" {
+			return nil, fmt.Errorf("missing value for %q in query", field+":")
+		}
+	}
+
+	term, err := newTerm(field, value)
+	if err != nil {
+		return nil, err
+	}
+	if negate {
+		return notNode{operand: term}, nil
+	}
+	return term, nil
+}
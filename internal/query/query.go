@@ -0,0 +1,472 @@
+// Package query implements the bookmark query language shared by the list,
+// export and scripting commands. A query is parsed once and can then be
+// compiled to an SQL condition or evaluated in memory against bookmarks.
+//
+// A query is a sequence of terms, implicitly joined with AND:
+//
+//	cat:work path:~/src -cat:archive added:<30d name:api*
+//
+// Terms are combined with AND, OR and NOT (upper case), negated with a
+// leading "-" and grouped with parentheses. The supported fields are:
+//
+//	cat:, category:, tag:  the bookmark has a matching tag
+//	path:                  the folder contains the value (~ is expanded)
+//	name:                  the folder's last path element matches the value
+//	alias:                 the bookmark's alias matches the value
+//	added:, visited:       the bookmark was added or last visited within an
+//	                       age (<30d, >2w) or before/after/on a date
+//	                       (<2024-01-01, >=2024-06-01, 2024-06-15)
+//
+// A term without a field matches the folder, any tag or the alias as a
+// substring, except that @name is short for alias:name. Values for cat:, path:,
+// name: and alias: may contain * and ? wildcards, in which case the whole value
+// has to match. Matching is case-insensitive.
+package query
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jhoffmann/bookmark-manager/internal/models"
+)
+
+// Query is a parsed query. The zero value matches every bookmark.
+type Query struct {
+	root node
+	text string
+}
+
+// node is an element of the parsed expression tree
+type node interface {
+	match(b *models.Bookmark, now time.Time) bool
+	sql(now time.Time) (string, []interface{})
+}
+
+// Parse parses a query. An empty or blank query matches every bookmark.
+func Parse(input string) (*Query, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	q := &Query{text: strings.TrimSpace(input)}
+	if len(tokens) == 0 {
+		return q, nil
+	}
+
+	q.root, err = p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("unexpected %q in query", p.peek().text)
+	}
+
+	return q, nil
+}
+
+// String returns the query as it was given to Parse
+func (q *Query) String() string {
+	return q.text
+}
+
+// Empty reports whether the query matches every bookmark
+func (q *Query) Empty() bool {
+	return q == nil || q.root == nil
+}
+
+// Match reports whether the bookmark satisfies the query. Relative dates are
+// evaluated against now.
+func (q *Query) Match(b *models.Bookmark, now time.Time) bool {
+	if q.Empty() {
+		return true
+	}
+	return q.root.match(b, now)
+}
+
+// Filter returns the bookmarks satisfying the query, keeping their order
+func (q *Query) Filter(bookmarks []*models.Bookmark, now time.Time) []*models.Bookmark {
+	if q.Empty() {
+		return bookmarks
+	}
+
+	filtered := make([]*models.Bookmark, 0, len(bookmarks))
+	for _, b := range bookmarks {
+		if q.root.match(b, now) {
+			filtered = append(filtered, b)
+		}
+	}
+	return filtered
+}
+
+// SQL compiles the query to a condition on the bookmarks table and its
+// arguments, for use with gorm's Where. It returns an empty condition for an
+// empty query.
+func (q *Query) SQL(now time.Time) (string, []interface{}) {
+	if q.Empty() {
+		return "", nil
+	}
+	return q.root.sql(now)
+}
+
+// Expression nodes
+
+type andNode struct{ left, right node }
+
+func (n andNode) match(b *models.Bookmark, now time.Time) bool {
+	return n.left.match(b, now) && n.right.match(b, now)
+}
+
+func (n andNode) sql(now time.Time) (string, []interface{}) {
+	return binarySQL("AND", n.left, n.right, now)
+}
+
+type orNode struct{ left, right node }
+
+func (n orNode) match(b *models.Bookmark, now time.Time) bool {
+	return n.left.match(b, now) || n.right.match(b, now)
+}
+
+func (n orNode) sql(now time.Time) (string, []interface{}) {
+	return binarySQL("OR", n.left, n.right, now)
+}
+
+type notNode struct{ operand node }
+
+func (n notNode) match(b *models.Bookmark, now time.Time) bool {
+	return !n.operand.match(b, now)
+}
+
+func (n notNode) sql(now time.Time) (string, []interface{}) {
+	cond, args := n.operand.sql(now)
+	return "NOT (" + cond + ")", args
+}
+
+func binarySQL(op string, left, right node, now time.Time) (string, []interface{}) {
+	leftCond, leftArgs := left.sql(now)
+	rightCond, rightArgs := right.sql(now)
+	return "(" + leftCond + ") " + op + " (" + rightCond + ")", append(leftArgs, rightArgs...)
+}
+
+// Terms
+
+// tagSQL matches bookmarks having a tag whose name is LIKE the argument
+const tagSQL = `EXISTS (SELECT 1 FROM bookmark_tags JOIN tags ON tags.id = bookmark_tags.tag_id ` +
+	`WHERE bookmark_tags.bookmark_id = bookmarks.id AND tags.name LIKE ? ESCAPE '\')`
+
+// basenameSQL extracts the last path element of the folder like basename:
+// without trailing slashes, rtrim strips every character that is not a slash
+// from the end, leaving the parent path
+const basenameSQL = `substr(rtrim(bookmarks.folder, '/'), ` +
+	`length(rtrim(rtrim(bookmarks.folder, '/'), replace(rtrim(bookmarks.folder, '/'), '/', ''))) + 1)`
+
+// basename returns the last element of a folder path, ignoring trailing
+// slashes; it is empty for the root
+func basename(folder string) string {
+	folder = strings.TrimRight(filepath.ToSlash(folder), "/")
+	return folder[strings.LastIndex(folder, "/")+1:]
+}
+
+// textTerm matches the folder, any tag or the alias as a substring
+type textTerm struct{ value string }
+
+func (t textTerm) match(b *models.Bookmark, now time.Time) bool {
+	value := strings.ToLower(t.value)
+	if strings.Contains(strings.ToLower(b.Folder), value) || strings.Contains(strings.ToLower(b.Alias), value) {
+		return true
+	}
+	for _, tag := range b.TagNames() {
+		if strings.Contains(strings.ToLower(tag), value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t textTerm) sql(now time.Time) (string, []interface{}) {
+	pattern := "%" + escapeLike(t.value) + "%"
+	return `bookmarks.folder LIKE ? ESCAPE '\' OR bookmarks.alias LIKE ? ESCAPE '\' OR ` + tagSQL,
+		[]interface{}{pattern, pattern, pattern}
+}
+
+// tagTerm matches bookmarks with a tag equal to, or matching, the pattern
+type tagTerm struct{ pattern pattern }
+
+func (t tagTerm) match(b *models.Bookmark, now time.Time) bool {
+	for _, tag := range b.TagNames() {
+		if t.pattern.match(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t tagTerm) sql(now time.Time) (string, []interface{}) {
+	return tagSQL, []interface{}{t.pattern.like()}
+}
+
+// pathTerm matches the whole folder against a pattern, or any part of it
+// when the value has no wildcards
+type pathTerm struct{ pattern pattern }
+
+func (t pathTerm) match(b *models.Bookmark, now time.Time) bool {
+	if !t.pattern.wildcard {
+		return strings.Contains(strings.ToLower(b.Folder), strings.ToLower(t.pattern.value))
+	}
+	return t.pattern.match(b.Folder)
+}
+
+func (t pathTerm) sql(now time.Time) (string, []interface{}) {
+	like := t.pattern.like()
+	if !t.pattern.wildcard {
+		like = "%" + like + "%"
+	}
+	return `bookmarks.folder LIKE ? ESCAPE '\'`, []interface{}{like}
+}
+
+// nameTerm matches the last element of the folder path
+type nameTerm struct{ pattern pattern }
+
+func (t nameTerm) match(b *models.Bookmark, now time.Time) bool {
+	return t.pattern.match(basename(b.Folder))
+}
+
+func (t nameTerm) sql(now time.Time) (string, []interface{}) {
+	return basenameSQL + ` LIKE ? ESCAPE '\'`, []interface{}{t.pattern.like()}
+}
+
+// aliasTerm matches bookmarks that have an alias equal to, or matching, the
+// pattern
+type aliasTerm struct{ pattern pattern }
+
+func (t aliasTerm) match(b *models.Bookmark, now time.Time) bool {
+	return b.Alias != "" && t.pattern.match(b.Alias)
+}
+
+func (t aliasTerm) sql(now time.Time) (string, []interface{}) {
+	return `bookmarks.alias != '' AND bookmarks.alias LIKE ? ESCAPE '\'`, []interface{}{t.pattern.like()}
+}
+
+// timeTerm matches a timestamp column against a time range
+type timeTerm struct {
+	column string
+	get    func(b *models.Bookmark) *time.Time
+	rng    timeRange
+}
+
+func (t timeTerm) match(b *models.Bookmark, now time.Time) bool {
+	ts := t.get(b)
+	if ts == nil || ts.IsZero() {
+		return false
+	}
+	from, to := t.rng.bounds(now)
+	return (from.IsZero() || !ts.Before(from)) && (to.IsZero() || ts.Before(to))
+}
+
+func (t timeTerm) sql(now time.Time) (string, []interface{}) {
+	from, to := t.rng.bounds(now)
+	conds := []string{"bookmarks." + t.column + " IS NOT NULL"}
+	var args []interface{}
+	if !from.IsZero() {
+		conds = append(conds, "bookmarks."+t.column+" >= ?")
+		args = append(args, from)
+	}
+	if !to.IsZero() {
+		conds = append(conds, "bookmarks."+t.column+" < ?")
+		args = append(args, to)
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+// timeRange is a comparison against either an age relative to now or a date
+type timeRange struct {
+	op   string        // One of "", "<", "<=", ">", ">="
+	age  time.Duration // Set for relative comparisons
+	date time.Time     // Set for date comparisons (midnight, local time)
+}
+
+// bounds returns the half-open interval [from, to) the range covers; a zero
+// time means unbounded
+func (r timeRange) bounds(now time.Time) (from, to time.Time) {
+	if r.date.IsZero() {
+		// Ages compare the other way round: added:<30d means newer than 30 days ago
+		cutoff := now.Add(-r.age)
+		switch r.op {
+		case ">", ">=":
+			return time.Time{}, cutoff
+		default:
+			return cutoff, time.Time{}
+		}
+	}
+
+	nextDay := r.date.AddDate(0, 0, 1)
+	switch r.op {
+	case "<":
+		return time.Time{}, r.date
+	case "<=":
+		return time.Time{}, nextDay
+	case ">":
+		return nextDay, time.Time{}
+	case ">=":
+		return r.date, time.Time{}
+	default:
+		return r.date, nextDay
+	}
+}
+
+// parseTimeRange parses values such as <30d, >=2w, <2024-01-01 or 2024-06-15
+func parseTimeRange(value string) (timeRange, error) {
+	var r timeRange
+	for _, op := range []string{"<=", ">=", "<", ">"} {
+		if rest, ok := strings.CutPrefix(value, op); ok {
+			r.op = op
+			value = rest
+			break
+		}
+	}
+
+	if date, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		r.date = date
+		return r, nil
+	}
+
+	age, err := ParseAge(value)
+	if err != nil {
+		return timeRange{}, fmt.Errorf("invalid age or date %q (expected e.g. <30d or >=2024-01-01)", value)
+	}
+	r.age = age
+	return r, nil
+}
+
+// ParseAge parses a Go duration, extended with day (d) and week (w) units
+func ParseAge(s string) (time.Duration, error) {
+	units := map[string]time.Duration{
+		"d": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour,
+	}
+	for suffix, unit := range units {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			value, err := strconv.ParseFloat(n, 64)
+			if err != nil || value < 0 {
+				return 0, fmt.Errorf("invalid age %q", s)
+			}
+			return time.Duration(value * float64(unit)), nil
+		}
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q (expected e.g. 30d, 2w or 36h)", s)
+	}
+	return d, nil
+}
+
+// pattern is a term value that may contain * and ? wildcards
+type pattern struct {
+	value    string
+	wildcard bool
+	re       *regexp.Regexp
+}
+
+func newPattern(value string) pattern {
+	p := pattern{value: value, wildcard: strings.ContainsAny(value, "*?")}
+	if p.wildcard {
+		expr := regexp.QuoteMeta(value)
+		expr = strings.ReplaceAll(expr, `\*`, ".*")
+		expr = strings.ReplaceAll(expr, `\?`, ".")
+		p.re = regexp.MustCompile("(?is)^" + expr + "$")
+	}
+	return p
+}
+
+// match reports whether s matches the whole pattern, ignoring case
+func (p pattern) match(s string) bool {
+	if p.wildcard {
+		return p.re.MatchString(s)
+	}
+	return strings.EqualFold(s, p.value)
+}
+
+// like converts the pattern to an SQL LIKE pattern using \ as escape
+func (p pattern) like() string {
+	var sb strings.Builder
+	for _, r := range p.value {
+		switch r {
+		case '*':
+			sb.WriteRune('%')
+		case '?':
+			sb.WriteRune('_')
+		case '%', '_', '\\':
+			sb.WriteRune('\\')
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// escapeLike escapes the LIKE wildcards in a literal value
+func escapeLike(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return r.Replace(s)
+}
+
+// expandHome replaces a leading ~ with the user's home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
+
+// newTerm builds the term for a field and value
+func newTerm(field, value string) (node, error) {
+	switch strings.ToLower(field) {
+	case "":
+		if alias, ok := strings.CutPrefix(value, models.AliasPrefix); ok && alias != "" {
+			return aliasTerm{pattern: newPattern(alias)}, nil
+		}
+		return textTerm{value: value}, nil
+	case "cat", "category", "tag":
+		return tagTerm{pattern: newPattern(value)}, nil
+	case "path":
+		return pathTerm{pattern: newPattern(expandHome(value))}, nil
+	case "name":
+		return nameTerm{pattern: newPattern(value)}, nil
+	case "alias":
+		return aliasTerm{pattern: newPattern(models.NormalizeAlias(value))}, nil
+	case "added":
+		r, err := parseTimeRange(value)
+		if err != nil {
+			return nil, err
+		}
+		return timeTerm{
+			column: "date_created",
+			get:    func(b *models.Bookmark) *time.Time { return &b.DateCreated },
+			rng:    r,
+		}, nil
+	case "visited":
+		r, err := parseTimeRange(value)
+		if err != nil {
+			return nil, err
+		}
+		return timeTerm{
+			column: "last_visited",
+			get:    func(b *models.Bookmark) *time.Time { return b.LastVisited },
+			rng:    r,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown query field %q (expected cat, tag, path, name, alias, added or visited)", field)
+	}
+}
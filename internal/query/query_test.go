@@ -0,0 +1,148 @@
+package query
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jhoffmann/bookmark-manager/internal/models"
+)
+
+func testBookmarks(now time.Time) []*models.Bookmark {
+	bookmark := func(folder string, age time.Duration, tags ...string) *models.Bookmark {
+		b := &models.Bookmark{Folder: folder, DateCreated: now.Add(-age)}
+		b.SetTagNames(tags)
+		return b
+	}
+	home, _ := os.UserHomeDir()
+	day := 24 * time.Hour
+
+	visited := now.Add(-2 * time.Hour)
+	api := bookmark("/work/src/api", 3*day, "work", "go")
+	api.LastVisited = &visited
+
+	gateway := bookmark("/work/src/api-gateway", 40*day, "work", "archive")
+	gateway.Alias = "gw"
+
+	return []*models.Bookmark{
+		api,
+		gateway,
+		bookmark(filepath.Join(home, "src", "notes"), 10*day, "personal"),
+		bookmark("/tmp/My Docs", 100*day),
+		bookmark("/srv/www/", 200*day),
+	}
+}
+
+func TestQuery_Match(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.Local)
+	bookmarks := testBookmarks(now)
+
+	tests := []struct {
+		query string
+		want  []int // Indexes into testBookmarks
+	}{
+		{"", []int{0, 1, 2, 3, 4}},
+		{"cat:work", []int{0, 1}},
+		{"cat:WORK -cat:archive", []int{0}},
+		{"tag:arch*", []int{1}},
+		{"path:~/src", []int{2}},
+		{"path:/work/*/api", []int{0}},
+		{"name:api*", []int{0, 1}},
+		{"name:api", []int{0}},
+		{"added:<30d", []int{0, 2}},
+		{"added:>30d", []int{1, 3, 4}},
+		{"added:2025-06-12", []int{0}},
+		{"added:<2025-06-01", []int{1, 3, 4}},
+		{"added:>=2025-06-05", []int{0, 2}},
+		{"visited:<1d", []int{0}},
+		{"NOT visited:<1d", []int{1, 2, 3, 4}},
+		{"cat:personal OR name:api", []int{0, 2}},
+		{"cat:work AND NOT (name:api OR added:<1d)", []int{1}},
+		{"gateway", []int{1}},
+		{"go", []int{0}},
+		{"gw", []int{1}},
+		{"alias:GW", []int{1}},
+		{"alias:*", []int{1}},
+		{"@gw", []int{1}},
+		{"-@*", []int{0, 2, 3, 4}},
+		{`path:"My Docs"`, []int{3}},
+		{"name:www", []int{4}},
+		{`"cat:work"`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tt.query, err)
+			}
+
+			var got []int
+			for i, b := range bookmarks {
+				if q.Match(b, now) {
+					got = append(got, i)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Match() selected %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Match() selected %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, input := range []string{
+		"owner:me",
+		"cat:",
+		"added:<soon",
+		"(cat:work",
+		"cat:work)",
+		"cat:work OR",
+		"AND cat:work",
+		`path:"unterminated`,
+	} {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q) should fail", input)
+		}
+	}
+}
+
+func TestPattern_Like(t *testing.T) {
+	tests := map[string]string{
+		"api*":     "api%",
+		"a?i":      "a_i",
+		"100%_off": `100\%\_off`,
+		`c:\dir`:   `c:\\dir`,
+	}
+	for value, want := range tests {
+		if got := newPattern(value).like(); got != want {
+			t.Errorf("like(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	tests := map[string]time.Duration{
+		"30d": 30 * 24 * time.Hour,
+		"2w":  14 * 24 * time.Hour,
+		"36h": 36 * time.Hour,
+	}
+	for input, want := range tests {
+		got, err := ParseAge(input)
+		if err != nil || got != want {
+			t.Errorf("ParseAge(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+
+	for _, input := range []string{"", "abc", "-3d", "-1h"} {
+		if _, err := ParseAge(input); err == nil {
+			t.Errorf("ParseAge(%q) should fail", input)
+		}
+	}
+}
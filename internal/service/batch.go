@@ -0,0 +1,119 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/jhoffmann/bookmark-manager/internal/models"
+	"gorm.io/gorm"
+)
+
+// The batch operations below act on several bookmarks in a single
+// transaction: either all of them are changed or, on the first error, none.
+
+// DeleteByIDs moves the bookmarks with the given IDs to the trash. It fails if
+// any of them does not exist or is already in the trash.
+func (s *Bookmarks) DeleteByIDs(ids []uint) error {
+	gormDB := s.db.GetDB()
+	if gormDB == nil {
+		return fmt.Errorf("database connection is not available")
+	}
+
+	return gormDB.Transaction(func(tx *gorm.DB) error {
+		for _, id := range ids {
+			var bookmark models.Bookmark
+			if err := tx.Select("id").First(&bookmark, id).Error; err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return fmt.Errorf("bookmark with ID %d %w", id, ErrNotFound)
+				}
+				return fmt.Errorf("failed to get bookmark: %w", err)
+			}
+			if err := tx.Delete(&bookmark).Error; err != nil {
+				return fmt.Errorf("failed to delete bookmark: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// RestoreByIDs moves the bookmarks with the given IDs out of the trash. It fails
+// if any of them is not in the trash, or if its folder or alias is in use,
+// including by another bookmark of the batch.
+func (s *Bookmarks) RestoreByIDs(ids []uint) ([]*models.Bookmark, error) {
+	gormDB := s.db.GetDB()
+	if gormDB == nil {
+		return nil, fmt.Errorf("database connection is not available")
+	}
+
+	restored := make([]*models.Bookmark, 0, len(ids))
+	err := gormDB.Transaction(func(tx *gorm.DB) error {
+		for _, id := range ids {
+			bookmark, err := s.restore(tx, id)
+			if err != nil {
+				return err
+			}
+			restored = append(restored, bookmark)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return restored, nil
+}
+
+// PurgeByIDs permanently deletes the bookmarks with the given IDs, which must
+// all be in the trash
+func (s *Bookmarks) PurgeByIDs(ids []uint) error {
+	gormDB := s.db.GetDB()
+	if gormDB == nil {
+		return fmt.Errorf("database connection is not available")
+	}
+
+	return gormDB.Transaction(func(tx *gorm.DB) error {
+		for _, id := range ids {
+			if _, err := s.getDeleted(tx, id); err != nil {
+				return err
+			}
+		}
+		return purgeWhere(tx, "id IN ?", ids)
+	})
+}
+
+// SetCategories changes the category of the bookmarks given by ID, replacing
+// their old category tag like Save does. It returns the previous labels,
+// which undo the change when passed to SetLabels.
+func (s *Bookmarks) SetCategories(categories map[uint]models.CategoryType) (map[uint]Labels, error) {
+	gormDB := s.db.GetDB()
+	if gormDB == nil {
+		return nil, fmt.Errorf("database connection is not available")
+	}
+
+	previous := make(map[uint]Labels, len(categories))
+	err := gormDB.Transaction(func(tx *gorm.DB) error {
+		for id, category := range categories {
+			var bookmark models.Bookmark
+			if err := tx.Preload("Tags").First(&bookmark, id).Error; err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return fmt.Errorf("bookmark with ID %d %w", id, ErrNotFound)
+				}
+				return fmt.Errorf("failed to get bookmark: %w", err)
+			}
+
+			previous[id] = labelsOf(&bookmark)
+			bookmark.Category = category
+			if err := bookmark.Validate(); err != nil {
+				return fmt.Errorf("validation failed for %s: %w", bookmark.Folder, err)
+			}
+			if err := saveBookmark(tx, &bookmark); err != nil {
+				return fmt.Errorf("failed to update %s: %w", bookmark.Folder, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return previous, nil
+}
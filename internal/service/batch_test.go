@@ -0,0 +1,154 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jhoffmann/bookmark-manager/internal/models"
+)
+
+// saveAll saves bookmarks for the given folders in the category and returns
+// their IDs
+func saveAll(t *testing.T, s *Bookmarks, category models.CategoryType, folders ...string) []uint {
+	t.Helper()
+
+	ids := make([]uint, len(folders))
+	for i, folder := range folders {
+		b := &models.Bookmark{Folder: folder, Category: category}
+		if err := s.Save(b); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		ids[i] = b.ID
+	}
+	return ids
+}
+
+func TestBookmarks_DeleteByIDs(t *testing.T) {
+	s := newTestBookmarks(t)
+	ids := saveAll(t, s, "work", "/test/a", "/test/b", "/test/c")
+
+	// An unknown ID fails the whole batch
+	err := s.DeleteByIDs([]uint{ids[0], 999})
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("DeleteByIDs() error = %v, want ErrNotFound", err)
+	}
+	if deleted, _ := s.ListDeleted(); len(deleted) != 0 {
+		t.Fatalf("Expected a failed batch to delete nothing, got %d in the trash", len(deleted))
+	}
+
+	if err := s.DeleteByIDs(ids[:2]); err != nil {
+		t.Fatalf("DeleteByIDs() error = %v", err)
+	}
+	deleted, err := s.ListDeleted()
+	if err != nil {
+		t.Fatalf("ListDeleted() error = %v", err)
+	}
+	if len(deleted) != 2 {
+		t.Errorf("Expected 2 bookmarks in the trash, got %d", len(deleted))
+	}
+
+	// Bookmarks already in the trash can't be deleted again
+	if err := s.DeleteByIDs(ids[1:]); err == nil {
+		t.Error("DeleteByIDs() expected error for a bookmark in the trash")
+	}
+	if _, err := s.GetByID(ids[2]); err != nil {
+		t.Errorf("Expected the failed batch to keep /test/c, got %v", err)
+	}
+}
+
+func TestBookmarks_RestoreByIDs(t *testing.T) {
+	s := newTestBookmarks(t)
+	ids := saveAll(t, s, "work", "/test/a", "/test/b", "/test/again")
+	if err := s.DeleteByIDs(ids); err != nil {
+		t.Fatalf("DeleteByIDs() error = %v", err)
+	}
+
+	// One bookmark that can't be restored keeps the others in the trash
+	saveAll(t, s, "work", "/test/again")
+	if _, err := s.RestoreByIDs(ids); err == nil {
+		t.Fatal("RestoreByIDs() expected error for a folder bookmarked again")
+	}
+	if deleted, _ := s.ListDeleted(); len(deleted) != 3 {
+		t.Fatalf("Expected a failed batch to restore nothing, got %d in the trash", len(deleted))
+	}
+
+	restored, err := s.RestoreByIDs(ids[:2])
+	if err != nil {
+		t.Fatalf("RestoreByIDs() error = %v", err)
+	}
+	if len(restored) != 2 || restored[0].Folder != "/test/a" || restored[1].Folder != "/test/b" {
+		t.Errorf("RestoreByIDs() = %v, want /test/a and /test/b", restored)
+	}
+	for _, b := range restored {
+		if b.DeletedAt.Valid {
+			t.Errorf("Expected %s to have no deletion time", b.Folder)
+		}
+	}
+}
+
+func TestBookmarks_PurgeByIDs(t *testing.T) {
+	s := newTestBookmarks(t)
+	ids := saveAll(t, s, "work", "/test/a", "/test/b", "/test/c")
+	if err := s.DeleteByIDs(ids[:2]); err != nil {
+		t.Fatalf("DeleteByIDs() error = %v", err)
+	}
+
+	if err := s.PurgeByIDs(ids); err == nil {
+		t.Fatal("PurgeByIDs() expected error for a bookmark not in the trash")
+	}
+	if deleted, _ := s.ListDeleted(); len(deleted) != 2 {
+		t.Fatalf("Expected a failed batch to purge nothing, got %d in the trash", len(deleted))
+	}
+
+	if err := s.PurgeByIDs(ids[:2]); err != nil {
+		t.Fatalf("PurgeByIDs() error = %v", err)
+	}
+	if deleted, _ := s.ListDeleted(); len(deleted) != 0 {
+		t.Errorf("Expected empty trash, got %d bookmarks", len(deleted))
+	}
+
+	var links int64
+	s.db.GetDB().Table("bookmark_tags").Count(&links)
+	if links != 1 {
+		t.Errorf("Expected only the active bookmark's tag link to remain, got %d", links)
+	}
+}
+
+func TestBookmarks_SetCategories(t *testing.T) {
+	s := newTestBookmarks(t)
+	ids := saveAll(t, s, "work", "/test/a", "/test/b")
+
+	// An unknown ID fails the whole batch
+	_, err := s.SetCategories(map[uint]models.CategoryType{ids[0]: "home", 999: "home"})
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("SetCategories() error = %v, want ErrNotFound", err)
+	}
+	if b, _ := s.GetByID(ids[0]); b.Category != "work" {
+		t.Fatalf("Expected a failed batch to keep the category, got %q", b.Category)
+	}
+
+	previous, err := s.SetCategories(map[uint]models.CategoryType{ids[0]: "home", ids[1]: "home"})
+	if err != nil {
+		t.Fatalf("SetCategories() error = %v", err)
+	}
+	for _, id := range ids {
+		if previous[id].Category != "work" {
+			t.Errorf("previous[%d] = %v, want work", id, previous[id])
+		}
+		b, err := s.GetByID(id)
+		if err != nil {
+			t.Fatalf("GetByID() error = %v", err)
+		}
+		if b.Category != "home" || !b.HasTag("home") || b.HasTag("work") {
+			t.Errorf("Expected %s to move from work to home, got %q with tags %v", b.Folder, b.Category, b.TagNames())
+		}
+	}
+
+	// Passing the previous labels back undoes the change
+	if _, err := s.SetLabels(previous); err != nil {
+		t.Fatalf("SetLabels() error = %v", err)
+	}
+	if b, _ := s.GetByID(ids[1]); b.Category != "work" || b.HasTag("home") {
+		t.Errorf("Expected undo to restore work, got %q with tags %v", b.Category, b.TagNames())
+	}
+}
@@ -2,13 +2,44 @@
 package service
 
 import (
+	"errors"
 	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/jhoffmann/bookmark-manager/internal/database"
 	"github.com/jhoffmann/bookmark-manager/internal/models"
+	"github.com/jhoffmann/bookmark-manager/internal/query"
 	"gorm.io/gorm"
 )
 
+// ErrNotFound is returned (wrapped) when a requested bookmark does not exist
+var ErrNotFound = errors.New("not found")
+
+// SortOrder controls the ordering of listed bookmarks
+type SortOrder string
+
+const (
+	// SortByCategory orders bookmarks by category, then folder
+	SortByCategory SortOrder = "category"
+	// SortByFrecency orders bookmarks by visit frequency and recency, most used first
+	SortByFrecency SortOrder = "frecency"
+)
+
+// ParseSortOrder converts a string into a SortOrder, defaulting to SortByCategory
+// for an empty string
+func ParseSortOrder(s string) (SortOrder, error) {
+	switch SortOrder(s) {
+	case "", SortByCategory:
+		return SortByCategory, nil
+	case SortByFrecency:
+		return SortByFrecency, nil
+	}
+	return "", fmt.Errorf("unknown sort order %q (expected %q or %q)", s, SortByCategory, SortByFrecency)
+}
+
 // Bookmarks provides bookmark operations with database access
 type Bookmarks struct {
 	db database.DB
@@ -19,7 +50,7 @@ func NewBookmarks(db database.DB) *Bookmarks {
 	return &Bookmarks{db: db}
 }
 
-// Save saves the bookmark to the database
+// Save saves the bookmark and its tags to the database
 func (s *Bookmarks) Save(b *models.Bookmark) error {
 	if err := b.Validate(); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
@@ -30,21 +61,99 @@ func (s *Bookmarks) Save(b *models.Bookmark) error {
 		return fmt.Errorf("database connection is not available")
 	}
 
+	return gormDB.Transaction(func(tx *gorm.DB) error {
+		return saveBookmark(tx, b)
+	})
+}
+
+// saveBookmark creates or updates the bookmark within the given transaction,
+// keeping its category in sync with its tags
+func saveBookmark(tx *gorm.DB, b *models.Bookmark) error {
+	if err := checkAliasAvailable(tx, b); err != nil {
+		return err
+	}
+
+	names := b.TagNames()
+
+	if b.ID != 0 {
+		// When the category changed, the old category tag goes with it
+		var previous models.Bookmark
+		if err := tx.Unscoped().Select("category").First(&previous, b.ID).Error; err == nil &&
+			previous.Category != "" && previous.Category != b.Category {
+			kept := names[:0]
+			for _, name := range names {
+				if name != string(previous.Category) {
+					kept = append(kept, name)
+				}
+			}
+			names = kept
+		}
+	}
+
+	return storeBookmark(tx, b, names)
+}
+
+// storeBookmark creates or updates the bookmark with exactly the named tags,
+// plus its category
+func storeBookmark(tx *gorm.DB, b *models.Bookmark, names []string) error {
+	if b.Category != "" {
+		names = append([]string{string(b.Category)}, names...)
+	}
+
+	tags, err := resolveTags(tx, models.NormalizeTagNames(names))
+	if err != nil {
+		return err
+	}
+
 	if b.ID == 0 {
 		// Create new bookmark
-		if err := gormDB.Create(b).Error; err != nil {
+		if err := tx.Omit("Tags").Create(b).Error; err != nil {
 			return fmt.Errorf("failed to create bookmark: %w", err)
 		}
 	} else {
 		// Update existing bookmark
-		if err := gormDB.Save(b).Error; err != nil {
+		if err := tx.Omit("Tags").Save(b).Error; err != nil {
 			return fmt.Errorf("failed to update bookmark: %w", err)
 		}
 	}
 
+	if err := tx.Model(b).Association("Tags").Replace(tags); err != nil {
+		return fmt.Errorf("failed to update bookmark tags: %w", err)
+	}
+	b.Tags = tags
+
 	return nil
 }
 
+// checkAliasAvailable fails when another bookmark outside the trash already
+// uses the bookmark's alias
+func checkAliasAvailable(tx *gorm.DB, b *models.Bookmark) error {
+	if b.Alias == "" {
+		return nil
+	}
+
+	var other models.Bookmark
+	err := tx.Select("id", "folder").Where("alias = ? AND id != ?", b.Alias, b.ID).First(&other).Error
+	switch {
+	case err == gorm.ErrRecordNotFound:
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to check alias: %w", err)
+	}
+	return fmt.Errorf("alias %q is already used by %s", b.Alias, other.Folder)
+}
+
+// resolveTags looks up the named tags, creating the ones that don't exist yet
+func resolveTags(tx *gorm.DB, names []string) ([]models.Tag, error) {
+	tags := make([]models.Tag, len(names))
+	for i, name := range names {
+		if err := tx.Where(models.Tag{Name: name}).FirstOrCreate(&tags[i]).Error; err != nil {
+			return nil, fmt.Errorf("failed to resolve tag %q: %w", name, err)
+		}
+	}
+	return tags, nil
+}
+
 // Delete removes the bookmark from the database (soft delete)
 func (s *Bookmarks) Delete(b *models.Bookmark) error {
 	if b.ID == 0 {
@@ -71,9 +180,27 @@ func (s *Bookmarks) GetByID(id uint) (*models.Bookmark, error) {
 	}
 
 	var bookmark models.Bookmark
-	if err := gormDB.First(&bookmark, id).Error; err != nil {
+	if err := gormDB.Preload("Tags").First(&bookmark, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("bookmark with ID %d %w", id, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get bookmark: %w", err)
+	}
+
+	return &bookmark, nil
+}
+
+// GetByFolder retrieves the bookmark for the exact folder path
+func (s *Bookmarks) GetByFolder(folder string) (*models.Bookmark, error) {
+	gormDB := s.db.GetDB()
+	if gormDB == nil {
+		return nil, fmt.Errorf("database connection is not available")
+	}
+
+	var bookmark models.Bookmark
+	if err := gormDB.Preload("Tags").Where("folder = ?", folder).First(&bookmark).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("bookmark with ID %d not found", id)
+			return nil, fmt.Errorf("bookmark for folder %s %w", folder, ErrNotFound)
 		}
 		return nil, fmt.Errorf("failed to get bookmark: %w", err)
 	}
@@ -81,56 +208,249 @@ func (s *Bookmarks) GetByID(id uint) (*models.Bookmark, error) {
 	return &bookmark, nil
 }
 
+// GetByAlias retrieves the bookmark with the given alias
+func (s *Bookmarks) GetByAlias(alias string) (*models.Bookmark, error) {
+	gormDB := s.db.GetDB()
+	if gormDB == nil {
+		return nil, fmt.Errorf("database connection is not available")
+	}
+
+	alias = models.NormalizeAlias(alias)
+	if alias == "" {
+		return nil, fmt.Errorf("alias is empty")
+	}
+
+	var bookmark models.Bookmark
+	if err := gormDB.Preload("Tags").Where("alias = ?", alias).First(&bookmark).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("bookmark with alias %s%s %w", models.AliasPrefix, alias, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get bookmark: %w", err)
+	}
+
+	return &bookmark, nil
+}
+
+// ResolveAlias expands a reference such as "@api/cmd/server" to the aliased
+// folder joined with the path that follows the alias
+func (s *Bookmarks) ResolveAlias(ref string) (string, error) {
+	alias, subPath, ok := models.ParseAliasRef(ref)
+	if !ok {
+		return "", fmt.Errorf("invalid reference %q: expected %salias or %salias/path", ref, models.AliasPrefix, models.AliasPrefix)
+	}
+
+	bookmark, err := s.GetByAlias(alias)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(bookmark.Folder, filepath.FromSlash(subPath)), nil
+}
+
 // List retrieves all bookmarks with optional limit and offset
 func (s *Bookmarks) List(limit, offset int) ([]*models.Bookmark, error) {
+	return s.ListSorted(SortByCategory, limit, offset)
+}
+
+// ListSorted retrieves all bookmarks in the given order with optional limit and offset
+func (s *Bookmarks) ListSorted(order SortOrder, limit, offset int) ([]*models.Bookmark, error) {
+	return s.Find(nil, order, limit, offset)
+}
+
+// Find retrieves the bookmarks matching a query in the given order with
+// optional limit and offset. A nil or empty query matches every bookmark.
+func (s *Bookmarks) Find(q *query.Query, order SortOrder, limit, offset int) ([]*models.Bookmark, error) {
 	gormDB := s.db.GetDB()
 	if gormDB == nil {
 		return nil, fmt.Errorf("database connection is not available")
 	}
 
 	var bookmarks []*models.Bookmark
-	query := gormDB.Order("category, folder")
+	stmt := gormDB.Preload("Tags").Order("category, folder")
 
-	if limit > 0 {
-		query = query.Limit(limit)
+	if cond, args := q.SQL(time.Now()); cond != "" {
+		stmt = stmt.Where("("+cond+")", args...)
 	}
-	if offset > 0 {
-		query = query.Offset(offset)
+
+	// Frecency depends on the current time, so it is sorted and paged in memory
+	if order != SortByFrecency {
+		if limit > 0 {
+			stmt = stmt.Limit(limit)
+		}
+		if offset > 0 {
+			stmt = stmt.Offset(offset)
+		}
 	}
 
-	if err := query.Find(&bookmarks).Error; err != nil {
+	if err := stmt.Find(&bookmarks).Error; err != nil {
 		return nil, fmt.Errorf("failed to list bookmarks: %w", err)
 	}
 
+	if order == SortByFrecency {
+		SortByFrecencyScore(bookmarks, time.Now())
+
+		if offset > 0 {
+			if offset >= len(bookmarks) {
+				return []*models.Bookmark{}, nil
+			}
+			bookmarks = bookmarks[offset:]
+		}
+		if limit > 0 && limit < len(bookmarks) {
+			bookmarks = bookmarks[:limit]
+		}
+	}
+
 	return bookmarks, nil
 }
 
-// SearchByCategory searches for bookmarks by category
+// SortByFrecencyScore sorts bookmarks in place by descending frecency, keeping
+// the existing relative order for equal scores
+func SortByFrecencyScore(bookmarks []*models.Bookmark, now time.Time) {
+	sort.SliceStable(bookmarks, func(i, j int) bool {
+		return bookmarks[i].Frecency(now) > bookmarks[j].Frecency(now)
+	})
+}
+
+// RecordVisit increments the visit count of the bookmark and sets its last
+// visited time to now
+func (s *Bookmarks) RecordVisit(b *models.Bookmark) error {
+	if b.ID == 0 {
+		return fmt.Errorf("cannot record visit: ID is required")
+	}
+
+	gormDB := s.db.GetDB()
+	if gormDB == nil {
+		return fmt.Errorf("database connection is not available")
+	}
+
+	now := time.Now()
+	if err := gormDB.Model(b).UpdateColumns(map[string]interface{}{
+		"visit_count":  gorm.Expr("visit_count + 1"),
+		"last_visited": now,
+	}).Error; err != nil {
+		return fmt.Errorf("failed to record visit: %w", err)
+	}
+
+	b.VisitCount++
+	b.LastVisited = &now
+
+	return nil
+}
+
+// SearchByCategory searches for bookmarks in a category, which are those
+// carrying its tag whether it is their primary category or not
 func (s *Bookmarks) SearchByCategory(category models.CategoryType) ([]*models.Bookmark, error) {
+	if strings.TrimSpace(string(category)) == "" {
+		return nil, fmt.Errorf("category name is empty")
+	}
+	return s.SearchByTags([]string{string(category)})
+}
+
+// SearchByFolder searches for bookmarks by folder path (partial match)
+func (s *Bookmarks) SearchByFolder(folderPath string) ([]*models.Bookmark, error) {
 	gormDB := s.db.GetDB()
 	if gormDB == nil {
 		return nil, fmt.Errorf("database connection is not available")
 	}
 
 	var bookmarks []*models.Bookmark
-	if err := gormDB.Where("category = ?", category).Order("category, folder").Find(&bookmarks).Error; err != nil {
-		return nil, fmt.Errorf("failed to search bookmarks by category: %w", err)
+	if err := gormDB.Preload("Tags").Where("folder LIKE ?", "%"+folderPath+"%").Order("category, folder").Find(&bookmarks).Error; err != nil {
+		return nil, fmt.Errorf("failed to search bookmarks by folder: %w", err)
 	}
 
 	return bookmarks, nil
 }
 
-// SearchByFolder searches for bookmarks by folder path (partial match)
-func (s *Bookmarks) SearchByFolder(folderPath string) ([]*models.Bookmark, error) {
+// SearchByTags searches for bookmarks carrying all of the given tags
+func (s *Bookmarks) SearchByTags(tags []string) ([]*models.Bookmark, error) {
 	gormDB := s.db.GetDB()
 	if gormDB == nil {
 		return nil, fmt.Errorf("database connection is not available")
 	}
 
+	tags = models.NormalizeTagNames(tags)
+	query := gormDB.Preload("Tags").Order("category, folder")
+
+	if len(tags) > 0 {
+		tagged := gormDB.Table("bookmark_tags").
+			Select("bookmark_tags.bookmark_id").
+			Joins("JOIN tags ON tags.id = bookmark_tags.tag_id").
+			Where("tags.name IN ?", tags).
+			Group("bookmark_tags.bookmark_id").
+			Having("COUNT(DISTINCT tags.id) = ?", len(tags))
+		query = query.Where("id IN (?)", tagged)
+	}
+
 	var bookmarks []*models.Bookmark
-	if err := gormDB.Where("folder LIKE ?", "%"+folderPath+"%").Order("category, folder").Find(&bookmarks).Error; err != nil {
-		return nil, fmt.Errorf("failed to search bookmarks by folder: %w", err)
+	if err := query.Find(&bookmarks).Error; err != nil {
+		return nil, fmt.Errorf("failed to search bookmarks by tags: %w", err)
 	}
 
 	return bookmarks, nil
 }
+
+// ImportResult summarizes the outcome of an Import call
+type ImportResult struct {
+	Created   int
+	Skipped   int
+	Conflicts []*models.Bookmark
+}
+
+// Import creates the given bookmarks, deduplicating against existing folders.
+// Bookmarks whose folder already exists with the same category are skipped;
+// those whose folder exists with a different category are reported as conflicts
+// and left untouched. Folders in the trash are skipped too, so they can still
+// be restored. All creations happen in a single transaction.
+func (s *Bookmarks) Import(bookmarks []*models.Bookmark) (*ImportResult, error) {
+	for i, b := range bookmarks {
+		if err := b.Validate(); err != nil {
+			return nil, fmt.Errorf("validation failed for entry %d: %w", i+1, err)
+		}
+	}
+
+	gormDB := s.db.GetDB()
+	if gormDB == nil {
+		return nil, fmt.Errorf("database connection is not available")
+	}
+
+	var existing []*models.Bookmark
+	if err := gormDB.Unscoped().Find(&existing).Error; err != nil {
+		return nil, fmt.Errorf("failed to list bookmarks: %w", err)
+	}
+
+	// A folder may be in the trash and bookmarked again; the active bookmark
+	// decides
+	byFolder := make(map[string]*models.Bookmark, len(existing))
+	for _, b := range existing {
+		if current, ok := byFolder[b.Folder]; !ok || current.DeletedAt.Valid {
+			byFolder[b.Folder] = b
+		}
+	}
+
+	result := &ImportResult{}
+	err := gormDB.Transaction(func(tx *gorm.DB) error {
+		for _, b := range bookmarks {
+			if current, ok := byFolder[b.Folder]; ok {
+				if current.DeletedAt.Valid || current.Category == b.Category {
+					result.Skipped++
+				} else {
+					result.Conflicts = append(result.Conflicts, b)
+				}
+				continue
+			}
+
+			b.ID = 0
+			if err := saveBookmark(tx, b); err != nil {
+				return fmt.Errorf("failed to import bookmark %q: %w", b.Folder, err)
+			}
+			byFolder[b.Folder] = b
+			result.Created++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to import bookmarks: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,492 @@
+package service
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jhoffmann/bookmark-manager/internal/config"
+	"github.com/jhoffmann/bookmark-manager/internal/database"
+	"github.com/jhoffmann/bookmark-manager/internal/models"
+	"github.com/jhoffmann/bookmark-manager/internal/query"
+)
+
+// newTestBookmarks creates a bookmark service backed by an in-memory database
+func newTestBookmarks(t *testing.T) *Bookmarks {
+	t.Helper()
+
+	db, err := database.NewDatabase(&config.Config{
+		DatabasePath: ":memory:",
+		LogLevel:     "silent",
+	})
+	if err != nil {
+		t.Fatalf("NewDatabase() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return NewBookmarks(db)
+}
+
+func TestBookmarks_Import(t *testing.T) {
+	s := newTestBookmarks(t)
+
+	existing := []*models.Bookmark{
+		{Folder: "/test/same", Category: "work"},
+		{Folder: "/test/conflict", Category: "work"},
+	}
+	for _, b := range existing {
+		if err := s.Save(b); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	created := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	incoming := []*models.Bookmark{
+		{Folder: "/test/same", Category: "work"},
+		{Folder: "/test/conflict", Category: "personal"},
+		{ID: 42, Folder: "/test/new", Category: "personal", DateCreated: created},
+		{Folder: "/test/new", Category: "personal"},
+	}
+
+	result, err := s.Import(incoming)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	if result.Created != 1 {
+		t.Errorf("Expected 1 created, got %d", result.Created)
+	}
+	if result.Skipped != 2 {
+		t.Errorf("Expected 2 skipped, got %d", result.Skipped)
+	}
+	if len(result.Conflicts) != 1 || result.Conflicts[0].Folder != "/test/conflict" {
+		t.Errorf("Expected conflict for /test/conflict, got %v", result.Conflicts)
+	}
+
+	imported, err := s.SearchByFolder("/test/new")
+	if err != nil {
+		t.Fatalf("SearchByFolder() error = %v", err)
+	}
+	if len(imported) != 1 {
+		t.Fatalf("Expected 1 imported bookmark, got %d", len(imported))
+	}
+	if !imported[0].DateCreated.Equal(created) {
+		t.Errorf("Expected DateCreated %v, got %v", created, imported[0].DateCreated)
+	}
+	if imported[0].Category != "personal" {
+		t.Errorf("Expected category personal, got %s", imported[0].Category)
+	}
+
+	// The conflicting bookmark must be left untouched
+	conflict, err := s.SearchByFolder("/test/conflict")
+	if err != nil {
+		t.Fatalf("SearchByFolder() error = %v", err)
+	}
+	if conflict[0].Category != "work" {
+		t.Errorf("Expected conflicting bookmark to keep category work, got %s", conflict[0].Category)
+	}
+}
+
+func TestBookmarks_Import_Trashed(t *testing.T) {
+	s := newTestBookmarks(t)
+	incoming := func() []*models.Bookmark {
+		return []*models.Bookmark{{Folder: "/test/a", Category: "work"}}
+	}
+
+	if _, err := s.Import(incoming()); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	b, err := s.GetByFolder("/test/a")
+	if err != nil {
+		t.Fatalf("GetByFolder() error = %v", err)
+	}
+	if err := s.DeleteByIDs([]uint{b.ID}); err != nil {
+		t.Fatalf("DeleteByIDs() error = %v", err)
+	}
+
+	// Importing the folder again leaves it in the trash
+	result, err := s.Import(incoming())
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if result.Created != 0 || result.Skipped != 1 {
+		t.Errorf("Import() = %+v, want the trashed folder skipped", result)
+	}
+
+	if _, err := s.Restore(b.ID); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	all, err := s.List(0, 0)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(all) != 1 || all[0].ID != b.ID {
+		t.Errorf("Expected the restored bookmark only, got %v", all)
+	}
+}
+
+func TestBookmarks_Import_Invalid(t *testing.T) {
+	s := newTestBookmarks(t)
+
+	_, err := s.Import([]*models.Bookmark{{Folder: "/test/ok"}, {Folder: ""}})
+	if err == nil {
+		t.Fatal("Import() expected validation error, got nil")
+	}
+
+	all, err := s.List(0, 0)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(all) != 0 {
+		t.Errorf("Expected no bookmarks after failed import, got %d", len(all))
+	}
+}
+
+func TestBookmarks_RecordVisit(t *testing.T) {
+	s := newTestBookmarks(t)
+
+	b := &models.Bookmark{Folder: "/test/visited"}
+	if err := s.Save(b); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := s.RecordVisit(b); err != nil {
+			t.Fatalf("RecordVisit() error = %v", err)
+		}
+	}
+
+	if b.VisitCount != 2 || b.LastVisited == nil {
+		t.Errorf("Expected in-memory visit count 2 with last visited set, got %d / %v", b.VisitCount, b.LastVisited)
+	}
+
+	stored, err := s.GetByID(b.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if stored.VisitCount != 2 {
+		t.Errorf("Expected stored visit count 2, got %d", stored.VisitCount)
+	}
+	if stored.LastVisited == nil {
+		t.Error("Expected stored last visited time to be set")
+	}
+
+	if err := s.RecordVisit(&models.Bookmark{}); err == nil {
+		t.Error("RecordVisit() expected error for bookmark without ID")
+	}
+}
+
+func TestBookmarks_ListSorted_Frecency(t *testing.T) {
+	s := newTestBookmarks(t)
+
+	now := time.Now()
+	old := now.Add(-30 * 24 * time.Hour)
+	bookmarks := []*models.Bookmark{
+		{Folder: "/test/a", Category: "a"},
+		{Folder: "/test/old", Category: "b", VisitCount: 10, LastVisited: &old},
+		{Folder: "/test/recent", Category: "c", VisitCount: 3, LastVisited: &now},
+	}
+	for _, b := range bookmarks {
+		if err := s.Save(b); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	sorted, err := s.ListSorted(SortByFrecency, 0, 0)
+	if err != nil {
+		t.Fatalf("ListSorted() error = %v", err)
+	}
+
+	want := []string{"/test/recent", "/test/old", "/test/a"}
+	for i, folder := range want {
+		if sorted[i].Folder != folder {
+			t.Errorf("Position %d: expected %s, got %s", i, folder, sorted[i].Folder)
+		}
+	}
+
+	paged, err := s.ListSorted(SortByFrecency, 1, 1)
+	if err != nil {
+		t.Fatalf("ListSorted() error = %v", err)
+	}
+	if len(paged) != 1 || paged[0].Folder != "/test/old" {
+		t.Errorf("Expected page with /test/old, got %v", paged)
+	}
+}
+
+func TestParseSortOrder(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    SortOrder
+		wantErr bool
+	}{
+		{"", SortByCategory, false},
+		{"category", SortByCategory, false},
+		{"frecency", SortByFrecency, false},
+		{"bogus", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSortOrder(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSortOrder(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseSortOrder(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestBookmarks_Save_Tags(t *testing.T) {
+	s := newTestBookmarks(t)
+
+	b := &models.Bookmark{Folder: "/test/tagged", Category: "work"}
+	b.SetTagNames([]string{"go", " api ", "go", ""})
+	if err := s.Save(b); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	stored, err := s.GetByID(b.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	for _, tag := range []string{"work", "go", "api"} {
+		if !stored.HasTag(tag) {
+			t.Errorf("Expected tag %q, got %v", tag, stored.TagNames())
+		}
+	}
+	if len(stored.Tags) != 3 {
+		t.Errorf("Expected 3 tags, got %v", stored.TagNames())
+	}
+
+	// Changing the category replaces the old category tag
+	stored.Category = "personal"
+	if err := s.Save(stored); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	updated, err := s.GetByID(b.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if updated.HasTag("work") || !updated.HasTag("personal") {
+		t.Errorf("Expected work tag replaced by personal, got %v", updated.TagNames())
+	}
+	if !updated.HasTag("go") || !updated.HasTag("api") {
+		t.Errorf("Expected additional tags to be kept, got %v", updated.TagNames())
+	}
+}
+
+func TestBookmarks_SearchByTags(t *testing.T) {
+	s := newTestBookmarks(t)
+
+	bookmarks := []struct {
+		folder string
+		tags   []string
+	}{
+		{"/test/a", []string{"work", "go"}},
+		{"/test/b", []string{"work"}},
+		{"/test/c", []string{"go"}},
+	}
+	for _, tt := range bookmarks {
+		b := &models.Bookmark{Folder: tt.folder}
+		b.SetTagNames(tt.tags)
+		if err := s.Save(b); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		tags []string
+		want int
+	}{
+		{[]string{"work"}, 2},
+		{[]string{"go"}, 2},
+		{[]string{"work", "go"}, 1},
+		{[]string{"missing"}, 0},
+		{nil, 3},
+	}
+
+	for _, tt := range tests {
+		found, err := s.SearchByTags(tt.tags)
+		if err != nil {
+			t.Fatalf("SearchByTags(%v) error = %v", tt.tags, err)
+		}
+		if len(found) != tt.want {
+			t.Errorf("SearchByTags(%v) returned %d bookmarks, want %d", tt.tags, len(found), tt.want)
+		}
+	}
+}
+
+func TestBookmarks_GetByFolder(t *testing.T) {
+	s := newTestBookmarks(t)
+
+	for _, folder := range []string{"/test/project", "/test/project-two"} {
+		if err := s.Save(&models.Bookmark{Folder: folder}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	b, err := s.GetByFolder("/test/project")
+	if err != nil {
+		t.Fatalf("GetByFolder() error = %v", err)
+	}
+	if b.Folder != "/test/project" {
+		t.Errorf("Expected /test/project, got %s", b.Folder)
+	}
+
+	if _, err := s.GetByFolder("/test/proj"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetByFolder() error = %v, want ErrNotFound", err)
+	}
+
+	if _, err := s.GetByID(999); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetByID() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestBookmarks_Find(t *testing.T) {
+	s := newTestBookmarks(t)
+
+	now := time.Now()
+	visited := now.Add(-time.Hour)
+	bookmarks := []*models.Bookmark{
+		{Folder: "/work/src/api", Category: "work", DateCreated: now.Add(-72 * time.Hour), LastVisited: &visited},
+		{Folder: "/work/src/api-gateway", Category: "work", Alias: "gw", DateCreated: now.Add(-40 * 24 * time.Hour)},
+		{Folder: "/home/user/notes_2024", Category: "personal", DateCreated: now.Add(-240 * time.Hour)},
+		{Folder: "/srv/www/", DateCreated: now.Add(-480 * time.Hour)},
+	}
+	bookmarks[1].SetTagNames([]string{"archive"})
+	for _, b := range bookmarks {
+		if err := s.Save(b); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	// The SQL compilation must select the same bookmarks as the in-memory predicate
+	for _, input := range []string{
+		"",
+		"cat:work -cat:archive",
+		"tag:arch*",
+		"name:api*",
+		"name:notes_2024",
+		"name:notes?2024",
+		"name:www",
+		"name:w*",
+		"path:/work/src",
+		"added:<30d",
+		"added:>30d",
+		"visited:<1d",
+		"cat:personal OR name:api",
+		"NOT (cat:work AND gateway)",
+		"personal",
+		"gw",
+		"@g*",
+		"-alias:*",
+	} {
+		t.Run(input, func(t *testing.T) {
+			q, err := query.Parse(input)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", input, err)
+			}
+
+			found, err := s.Find(q, SortByCategory, 0, 0)
+			if err != nil {
+				t.Fatalf("Find() error = %v", err)
+			}
+
+			all, err := s.List(0, 0)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			want := q.Filter(all, time.Now())
+
+			if len(found) != len(want) {
+				t.Fatalf("Find(%q) returned %d bookmarks, predicate selects %d", input, len(found), len(want))
+			}
+			for i := range found {
+				if found[i].Folder != want[i].Folder {
+					t.Errorf("Find(%q)[%d] = %s, want %s", input, i, found[i].Folder, want[i].Folder)
+				}
+			}
+		})
+	}
+}
+
+func TestBookmarks_Alias(t *testing.T) {
+	s := newTestBookmarks(t)
+
+	api := &models.Bookmark{Folder: "/src/api", Alias: "api"}
+	if err := s.Save(api); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	got, err := s.GetByAlias("@api")
+	if err != nil {
+		t.Fatalf("GetByAlias() error = %v", err)
+	}
+	if got.ID != api.ID {
+		t.Errorf("GetByAlias() = %d, want %d", got.ID, api.ID)
+	}
+
+	if _, err := s.GetByAlias("web"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetByAlias() error = %v, want ErrNotFound", err)
+	}
+
+	// Aliases are unique
+	if err := s.Save(&models.Bookmark{Folder: "/src/other", Alias: "api"}); err == nil {
+		t.Error("Save() expected error for a duplicate alias, got nil")
+	}
+	if err := s.Save(&models.Bookmark{Folder: "/src/other", Alias: "42"}); err == nil {
+		t.Error("Save() expected error for a numeric alias, got nil")
+	}
+
+	// Saving the bookmark itself again is fine
+	api.Category = "work"
+	if err := s.Save(api); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// A trashed bookmark frees its alias, but cannot be restored while the
+	// alias is taken
+	if err := s.Delete(api); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := s.Save(&models.Bookmark{Folder: "/src/api2", Alias: "api"}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, err := s.Restore(api.ID); err == nil {
+		t.Error("Restore() expected error for a taken alias, got nil")
+	}
+}
+
+func TestBookmarks_ResolveAlias(t *testing.T) {
+	s := newTestBookmarks(t)
+
+	if err := s.Save(&models.Bookmark{Folder: "/src/api", Alias: "api"}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{ref: "@api", want: "/src/api"},
+		{ref: "@api/", want: "/src/api"},
+		{ref: "@api/cmd/server", want: "/src/api/cmd/server"},
+		{ref: "@web/cmd", wantErr: true},
+		{ref: "api", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := s.ResolveAlias(tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveAlias(%q) error = %v, wantErr %v", tt.ref, err, tt.wantErr)
+			}
+			if want := filepath.FromSlash(tt.want); got != want {
+				t.Errorf("ResolveAlias(%q) = %q, want %q", tt.ref, got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jhoffmann/bookmark-manager/internal/models"
+	"github.com/jhoffmann/bookmark-manager/internal/query"
+	"gorm.io/gorm"
+)
+
+// Categories are tags: a bookmark is in every category it has a tag for, and
+// its Category field only names the primary one. The operations below change
+// a tag on every bookmark outside the trash that carries it, in a single
+// transaction, keeping the primary category in step. They return the
+// previous labels of each changed bookmark by ID: its length is the number of
+// bookmarks changed, and passing it to SetLabels undoes the change.
+
+// Labels are the category and tags of a bookmark
+type Labels struct {
+	Category models.CategoryType
+	Tags     []string
+}
+
+// labelsOf returns the current labels of a bookmark
+func labelsOf(b *models.Bookmark) Labels {
+	return Labels{Category: b.Category, Tags: b.TagNames()}
+}
+
+// CategoryCount is a category and the number of bookmarks in it
+type CategoryCount struct {
+	Category models.CategoryType
+	Count    int
+}
+
+// CategoryCounts returns the categories of bookmarks outside the trash with
+// their number of bookmarks, sorted by name. A bookmark with several tags
+// counts in each of them; bookmarks without any are counted under the empty
+// category, which comes first. Only the bookmarks matching the query are
+// counted; a nil or empty query counts every bookmark, as with Find.
+func (s *Bookmarks) CategoryCounts(q *query.Query) ([]CategoryCount, error) {
+	gormDB := s.db.GetDB()
+	if gormDB == nil {
+		return nil, fmt.Errorf("database connection is not available")
+	}
+
+	untaggedStmt := gormDB.Model(&models.Bookmark{}).
+		Where("id NOT IN (?)", gormDB.Table("bookmark_tags").Select("bookmark_id"))
+	taggedStmt := gormDB.Table("bookmark_tags").
+		Select("tags.name AS category, COUNT(*) AS count").
+		Joins("JOIN tags ON tags.id = bookmark_tags.tag_id").
+		Joins("JOIN bookmarks ON bookmarks.id = bookmark_tags.bookmark_id").
+		Where("bookmarks.deleted_at IS NULL")
+	if cond, args := q.SQL(time.Now()); cond != "" {
+		untaggedStmt = untaggedStmt.Where("("+cond+")", args...)
+		taggedStmt = taggedStmt.Where("("+cond+")", args...)
+	}
+
+	var untagged int64
+	if err := untaggedStmt.Count(&untagged).Error; err != nil {
+		return nil, fmt.Errorf("failed to count categories: %w", err)
+	}
+
+	var counts []CategoryCount
+	if err := taggedStmt.
+		Group("tags.name").
+		Order("tags.name").
+		Scan(&counts).Error; err != nil {
+		return nil, fmt.Errorf("failed to count categories: %w", err)
+	}
+
+	if untagged > 0 {
+		counts = append([]CategoryCount{{Count: int(untagged)}}, counts...)
+	}
+	return counts, nil
+}
+
+// RenameCategory gives the bookmarks of a category a new one, which must not
+// be in use yet; see MergeCategories to combine categories
+func (s *Bookmarks) RenameCategory(from, to string) (map[uint]Labels, error) {
+	from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+	if to == "" {
+		return nil, fmt.Errorf("the new category name is empty")
+	}
+	if from == to {
+		return nil, fmt.Errorf("category %q already has this name", from)
+	}
+
+	return s.recategorize([]string{from}, to, func(tx *gorm.DB) error {
+		var existing int64
+		if err := tagged(tx, []string{to}).Count(&existing).Error; err != nil {
+			return fmt.Errorf("failed to check category: %w", err)
+		}
+		if existing > 0 {
+			return fmt.Errorf("category %q already exists (merge the categories instead)", to)
+		}
+		return nil
+	})
+}
+
+// MergeCategories moves the bookmarks of the source categories into another
+// category, which is created when it is not in use yet
+func (s *Bookmarks) MergeCategories(sources []string, into string) (map[uint]Labels, error) {
+	into = strings.TrimSpace(into)
+	if into == "" {
+		return nil, fmt.Errorf("the target category name is empty")
+	}
+	trimmed := make([]string, len(sources))
+	for i, source := range sources {
+		trimmed[i] = strings.TrimSpace(source)
+		if trimmed[i] == into {
+			return nil, fmt.Errorf("cannot merge category %q into itself", into)
+		}
+	}
+
+	return s.recategorize(trimmed, into, nil)
+}
+
+// RemoveCategories removes categories from their bookmarks, which keep their
+// other tags; those whose primary category it was are left without one
+func (s *Bookmarks) RemoveCategories(names ...string) (map[uint]Labels, error) {
+	trimmed := make([]string, len(names))
+	for i, name := range names {
+		trimmed[i] = strings.TrimSpace(name)
+	}
+	return s.recategorize(trimmed, "", nil)
+}
+
+// SetLabels gives the bookmarks the labels stored by ID, as returned by the
+// category operations. It returns the labels they had before, which undo the
+// change in turn.
+func (s *Bookmarks) SetLabels(labels map[uint]Labels) (map[uint]Labels, error) {
+	gormDB := s.db.GetDB()
+	if gormDB == nil {
+		return nil, fmt.Errorf("database connection is not available")
+	}
+
+	previous := make(map[uint]Labels, len(labels))
+	err := gormDB.Transaction(func(tx *gorm.DB) error {
+		for id, l := range labels {
+			var bookmark models.Bookmark
+			if err := tx.Preload("Tags").First(&bookmark, id).Error; err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return fmt.Errorf("bookmark with ID %d %w", id, ErrNotFound)
+				}
+				return fmt.Errorf("failed to get bookmark: %w", err)
+			}
+
+			previous[id] = labelsOf(&bookmark)
+			bookmark.Category = l.Category
+			if err := storeBookmark(tx, &bookmark, l.Tags); err != nil {
+				return fmt.Errorf("failed to update %s: %w", bookmark.Folder, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return previous, nil
+}
+
+// recategorize replaces the tags of the categories with another one, or
+// drops them for an empty one, after the check passes. It fails when a
+// category has no bookmarks.
+func (s *Bookmarks) recategorize(from []string, to string, check func(tx *gorm.DB) error) (map[uint]Labels, error) {
+	if err := models.ValidateTagName(to); err != nil {
+		return nil, fmt.Errorf("invalid category: %w", err)
+	}
+
+	gormDB := s.db.GetDB()
+	if gormDB == nil {
+		return nil, fmt.Errorf("database connection is not available")
+	}
+
+	previous := make(map[uint]Labels)
+	err := gormDB.Transaction(func(tx *gorm.DB) error {
+		if check != nil {
+			if err := check(tx); err != nil {
+				return err
+			}
+		}
+
+		var bookmarks []*models.Bookmark
+		if err := tx.Preload("Tags").Where("id IN (?)", taggedIDs(tx, from)).Find(&bookmarks).Error; err != nil {
+			return fmt.Errorf("failed to find bookmarks: %w", err)
+		}
+		for _, category := range from {
+			if category == "" {
+				return fmt.Errorf("category name is empty")
+			}
+			if !slices.ContainsFunc(bookmarks, func(b *models.Bookmark) bool { return b.HasTag(category) }) {
+				return fmt.Errorf("category %q %w", category, ErrNotFound)
+			}
+		}
+
+		for _, b := range bookmarks {
+			previous[b.ID] = labelsOf(b)
+
+			var names []string
+			for _, name := range b.TagNames() {
+				switch {
+				case !slices.Contains(from, name):
+					names = append(names, name)
+				case to != "":
+					names = append(names, to)
+				}
+			}
+			if slices.Contains(from, string(b.Category)) {
+				b.Category = models.CategoryType(to)
+			}
+			if err := b.Validate(); err != nil {
+				return fmt.Errorf("validation failed for %s: %w", b.Folder, err)
+			}
+
+			if err := storeBookmark(tx, b, names); err != nil {
+				return fmt.Errorf("failed to update %s: %w", b.Folder, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return previous, nil
+}
+
+// taggedIDs selects the IDs of bookmarks carrying any of the named tags
+func taggedIDs(tx *gorm.DB, names []string) *gorm.DB {
+	return tx.Table("bookmark_tags").
+		Select("bookmark_tags.bookmark_id").
+		Joins("JOIN tags ON tags.id = bookmark_tags.tag_id").
+		Where("tags.name IN ?", names)
+}
+
+// tagged selects the bookmarks outside the trash carrying any of the named
+// tags
+func tagged(tx *gorm.DB, names []string) *gorm.DB {
+	return tx.Model(&models.Bookmark{}).Where("id IN (?)", taggedIDs(tx, names))
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jhoffmann/bookmark-manager/internal/models"
+	"github.com/jhoffmann/bookmark-manager/internal/query"
+)
+
+// saveTagged saves a bookmark with a category and further tags, returning its ID
+func saveTagged(t *testing.T, s *Bookmarks, folder string, category models.CategoryType, tags ...string) uint {
+	t.Helper()
+
+	b := &models.Bookmark{Folder: folder, Category: category}
+	b.SetTagNames(tags)
+	if err := s.Save(b); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	return b.ID
+}
+
+func TestBookmarks_CategoryCounts(t *testing.T) {
+	s := newTestBookmarks(t)
+	saveAll(t, s, "work", "/test/a", "/test/b")
+	saveTagged(t, s, "/test/c", "home", "go")
+	saveAll(t, s, "", "/test/d")
+	trashed := saveAll(t, s, "old", "/test/e")
+	if err := s.DeleteByIDs(trashed); err != nil {
+		t.Fatalf("DeleteByIDs() error = %v", err)
+	}
+
+	counts, err := s.CategoryCounts(nil)
+	if err != nil {
+		t.Fatalf("CategoryCounts() error = %v", err)
+	}
+	want := []CategoryCount{{"", 1}, {"go", 1}, {"home", 1}, {"work", 2}}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("CategoryCounts() = %v, want %v", counts, want)
+	}
+
+	// A query limits the bookmarks counted
+	q, err := query.Parse("path:/test/b OR path:/test/c")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	counts, err = s.CategoryCounts(q)
+	if err != nil {
+		t.Fatalf("CategoryCounts() error = %v", err)
+	}
+	want = []CategoryCount{{"go", 1}, {"home", 1}, {"work", 1}}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("CategoryCounts(%q) = %v, want %v", "path:/test/b OR path:/test/c", counts, want)
+	}
+}
+
+func TestBookmarks_RenameCategory(t *testing.T) {
+	s := newTestBookmarks(t)
+	ids := saveAll(t, s, "wrk", "/test/a", "/test/b")
+	tagged := saveTagged(t, s, "/test/c", "home", "wrk")
+
+	tests := []struct {
+		name     string
+		from, to string
+	}{
+		{"unknown", "nope", "work"},
+		{"empty", "wrk", " "},
+		{"same", "wrk", "wrk"},
+		{"existing", "wrk", "home"},
+		{"too long", "wrk", strings.Repeat("w", 51)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.RenameCategory(tt.from, tt.to); err == nil {
+				t.Errorf("RenameCategory(%q, %q) expected error", tt.from, tt.to)
+			}
+		})
+	}
+
+	previous, err := s.RenameCategory("wrk", "work")
+	if err != nil {
+		t.Fatalf("RenameCategory() error = %v", err)
+	}
+	if len(previous) != 3 || previous[ids[0]].Category != "wrk" {
+		t.Errorf("RenameCategory() = %v, want the three bookmarks tagged wrk", previous)
+	}
+	b, err := s.GetByID(ids[1])
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if b.Category != "work" || !b.HasTag("work") || b.HasTag("wrk") {
+		t.Errorf("Expected the category and its tag renamed, got %q with tags %v", b.Category, b.TagNames())
+	}
+
+	// Where the category is not the primary one, only the tag changes
+	b, err = s.GetByID(tagged)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if b.Category != "home" || !b.HasTag("work") || b.HasTag("wrk") {
+		t.Errorf("Expected home with the tag renamed, got %q with tags %v", b.Category, b.TagNames())
+	}
+}
+
+func TestBookmarks_MergeCategories(t *testing.T) {
+	s := newTestBookmarks(t)
+	saveAll(t, s, "wrk", "/test/a")
+	both := saveTagged(t, s, "/test/b", "job", "work")
+	saveAll(t, s, "work", "/test/c")
+
+	// A missing source fails the whole merge
+	_, err := s.MergeCategories([]string{"wrk", "nope"}, "work")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("MergeCategories() error = %v, want ErrNotFound", err)
+	}
+	if _, err := s.MergeCategories([]string{"work"}, "work"); err == nil {
+		t.Error("MergeCategories() expected error merging a category into itself")
+	}
+
+	previous, err := s.MergeCategories([]string{"wrk", "job"}, "work")
+	if err != nil {
+		t.Fatalf("MergeCategories() error = %v", err)
+	}
+	if len(previous) != 2 {
+		t.Errorf("Expected 2 bookmarks moved, got %v", previous)
+	}
+	counts, err := s.CategoryCounts(nil)
+	if err != nil {
+		t.Fatalf("CategoryCounts() error = %v", err)
+	}
+	if want := []CategoryCount{{"work", 3}}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("CategoryCounts() = %v, want %v", counts, want)
+	}
+
+	// The previous labels undo the merge, including tags the bookmarks had
+	// in both categories
+	if _, err := s.SetLabels(previous); err != nil {
+		t.Fatalf("SetLabels() error = %v", err)
+	}
+	if counts, _ := s.CategoryCounts(nil); len(counts) != 3 {
+		t.Errorf("Expected the three categories back, got %v", counts)
+	}
+	b, err := s.GetByID(both)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if b.Category != "job" || !b.HasTag("job") || !b.HasTag("work") {
+		t.Errorf("Expected job tagged work again, got %q with tags %v", b.Category, b.TagNames())
+	}
+}
+
+func TestBookmarks_RemoveCategories(t *testing.T) {
+	s := newTestBookmarks(t)
+	ids := saveAll(t, s, "work", "/test/a", "/test/b")
+	tagged := saveTagged(t, s, "/test/c", "home", "work")
+	old := saveAll(t, s, "old", "/test/d")
+
+	// A missing category fails the whole removal
+	if _, err := s.RemoveCategories("old", "nope"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("RemoveCategories() error = %v, want ErrNotFound", err)
+	}
+	b, err := s.GetByID(old[0])
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if b.Category != "old" {
+		t.Errorf("Expected old to be kept after a failed removal, got %q", b.Category)
+	}
+
+	previous, err := s.RemoveCategories("work", "old")
+	if err != nil {
+		t.Fatalf("RemoveCategories() error = %v", err)
+	}
+	if len(previous) != 4 {
+		t.Errorf("Expected 4 bookmarks changed, got %v", previous)
+	}
+	b, err = s.GetByID(ids[0])
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if b.Category != models.CategoryType("") || len(b.Tags) != 0 {
+		t.Errorf("Expected no category and no tags, got %q with tags %v", b.Category, b.TagNames())
+	}
+	b, err = s.GetByID(tagged)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if b.Category != "home" || b.HasTag("work") {
+		t.Errorf("Expected home without the work tag, got %q with tags %v", b.Category, b.TagNames())
+	}
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/jhoffmann/bookmark-manager/internal/models"
+	"gorm.io/gorm"
+)
+
+// The queries below back shell completion, which runs on every keystroke, so
+// they select only the columns needed, match prefixes and are limited. A limit
+// of zero or less returns every match.
+
+// TagNamesWithPrefix returns the names of tags in use by bookmarks starting
+// with prefix, sorted. They are the categories, see CategoryCounts.
+func (s *Bookmarks) TagNamesWithPrefix(prefix string, limit int) ([]string, error) {
+	gormDB := s.db.GetDB()
+	if gormDB == nil {
+		return nil, fmt.Errorf("database connection is not available")
+	}
+
+	inUse := gormDB.Table("bookmark_tags").
+		Select("bookmark_tags.tag_id").
+		Joins("JOIN bookmarks ON bookmarks.id = bookmark_tags.bookmark_id").
+		Where("bookmarks.deleted_at IS NULL")
+
+	var names []string
+	stmt := gormDB.Model(&models.Tag{}).
+		Where("name LIKE ? ESCAPE '\\' AND id IN (?)", likePrefix(prefix), inUse).
+		Order("name")
+	if err := withLimit(stmt, limit).Pluck("name", &names).Error; err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	return names, nil
+}
+
+// FindByAliasPrefix returns the bookmarks whose alias starts with prefix,
+// ordered by alias. Only the ID, folder and alias are loaded.
+func (s *Bookmarks) FindByAliasPrefix(prefix string, limit int) ([]*models.Bookmark, error) {
+	gormDB := s.db.GetDB()
+	if gormDB == nil {
+		return nil, fmt.Errorf("database connection is not available")
+	}
+
+	var bookmarks []*models.Bookmark
+	stmt := gormDB.Select("id", "folder", "alias").
+		Where("alias != '' AND alias LIKE ? ESCAPE '\\'", likePrefix(models.NormalizeAlias(prefix))).
+		Order("alias")
+	if err := withLimit(stmt, limit).Find(&bookmarks).Error; err != nil {
+		return nil, fmt.Errorf("failed to list aliases: %w", err)
+	}
+
+	return bookmarks, nil
+}
+
+// FindByIDPrefix returns the bookmarks whose ID starts with the given digits,
+// in ID order, from the trash when deleted is set. Only the ID, folder and
+// alias are loaded.
+func (s *Bookmarks) FindByIDPrefix(prefix string, deleted bool, limit int) ([]*models.Bookmark, error) {
+	gormDB := s.db.GetDB()
+	if gormDB == nil {
+		return nil, fmt.Errorf("database connection is not available")
+	}
+
+	stmt := gormDB.Select("id", "folder", "alias")
+	if deleted {
+		stmt = stmt.Unscoped().Where("deleted_at IS NOT NULL")
+	}
+	if prefix != "" {
+		stmt = stmt.Where("CAST(id AS TEXT) LIKE ? ESCAPE '\\'", likePrefix(prefix))
+	}
+
+	var bookmarks []*models.Bookmark
+	if err := withLimit(stmt.Order("id"), limit).Find(&bookmarks).Error; err != nil {
+		return nil, fmt.Errorf("failed to list bookmarks: %w", err)
+	}
+
+	return bookmarks, nil
+}
+
+// FolderNames returns the distinct last elements of bookmarked folders that
+// start with prefix, sorted
+func (s *Bookmarks) FolderNames(prefix string, limit int) ([]string, error) {
+	gormDB := s.db.GetDB()
+	if gormDB == nil {
+		return nil, fmt.Errorf("database connection is not available")
+	}
+
+	// A folder whose name starts with the prefix has it right after a slash;
+	// the names are extracted and deduplicated here
+	var folders []string
+	stmt := gormDB.Model(&models.Bookmark{}).
+		Where("folder LIKE ? ESCAPE '\\'", "%/"+likePrefix(prefix)).
+		Order("folder")
+	if err := stmt.Pluck("folder", &folders).Error; err != nil {
+		return nil, fmt.Errorf("failed to list folders: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, folder := range folders {
+		name := path.Base(strings.ReplaceAll(folder, `\`, "/"))
+		if seen[name] || !strings.HasPrefix(strings.ToLower(name), strings.ToLower(prefix)) {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+		if limit > 0 && len(names) == limit {
+			break
+		}
+	}
+
+	return names, nil
+}
+
+// likePrefix builds a LIKE pattern matching strings that start with prefix,
+// using \ as the escape character
+func likePrefix(prefix string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	return replacer.Replace(prefix) + "%"
+}
+
+// withLimit limits the statement when limit is positive
+func withLimit(stmt *gorm.DB, limit int) *gorm.DB {
+	if limit > 0 {
+		return stmt.Limit(limit)
+	}
+	return stmt
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/jhoffmann/bookmark-manager/internal/models"
+)
+
+func TestBookmarks_CompletionQueries(t *testing.T) {
+	s := newTestBookmarks(t)
+
+	bookmarks := []*models.Bookmark{
+		{Folder: "/src/api", Category: "work", Alias: "api"},
+		{Folder: "/src/api-gateway", Category: "work", Alias: "gateway"},
+		{Folder: "/home/user/notes", Category: "personal"},
+		{Folder: "/old/work_100", Category: "wonder", Alias: "ancient"},
+	}
+	bookmarks[0].SetTagNames([]string{"go"})
+	for _, b := range bookmarks {
+		if err := s.Save(b); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+	if err := s.Delete(bookmarks[3]); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	equal := func(t *testing.T, got, want []string) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Fatalf("got %v, want %v", got, want)
+			}
+		}
+	}
+
+	// The trashed bookmark's category and alias are not offered
+	t.Run("tags", func(t *testing.T) {
+		got, err := s.TagNamesWithPrefix("", 0)
+		if err != nil {
+			t.Fatalf("TagNamesWithPrefix() error = %v", err)
+		}
+		equal(t, got, []string{"go", "personal", "work"})
+	})
+
+	t.Run("aliases", func(t *testing.T) {
+		found, err := s.FindByAliasPrefix("@a", 0)
+		if err != nil {
+			t.Fatalf("FindByAliasPrefix() error = %v", err)
+		}
+		if len(found) != 1 || found[0].Folder != "/src/api" {
+			t.Errorf("FindByAliasPrefix() = %v, want /src/api", found)
+		}
+	})
+
+	t.Run("ids", func(t *testing.T) {
+		found, err := s.FindByIDPrefix("", false, 2)
+		if err != nil {
+			t.Fatalf("FindByIDPrefix() error = %v", err)
+		}
+		if len(found) != 2 || found[0].ID != bookmarks[0].ID {
+			t.Errorf("FindByIDPrefix() = %v, want the first two bookmarks", found)
+		}
+
+		trashed, err := s.FindByIDPrefix("", true, 0)
+		if err != nil {
+			t.Fatalf("FindByIDPrefix() error = %v", err)
+		}
+		if len(trashed) != 1 || trashed[0].ID != bookmarks[3].ID {
+			t.Errorf("FindByIDPrefix(deleted) = %v, want the trashed bookmark", trashed)
+		}
+	})
+
+	t.Run("folder names", func(t *testing.T) {
+		got, err := s.FolderNames("API", 0)
+		if err != nil {
+			t.Fatalf("FolderNames() error = %v", err)
+		}
+		equal(t, got, []string{"api", "api-gateway"})
+	})
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/jhoffmann/bookmark-manager/internal/models"
+)
+
+// FolderStatus describes the state of a bookmarked folder on disk
+type FolderStatus string
+
+const (
+	// FolderOK means the folder exists and can be read
+	FolderOK FolderStatus = "ok"
+	// FolderMissing means nothing exists at the path
+	FolderMissing FolderStatus = "missing"
+	// FolderBrokenSymlink means the path is a symlink whose target does not exist
+	FolderBrokenSymlink FolderStatus = "broken symlink"
+	// FolderNotDirectory means the path exists but is not a directory
+	FolderNotDirectory FolderStatus = "not a directory"
+	// FolderUnreadable means the folder exists but cannot be listed
+	FolderUnreadable FolderStatus = "unreadable"
+)
+
+// CheckFolder stats the path and reports whether it is a usable folder
+func (fs *Folders) CheckFolder(path string) FolderStatus {
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			// Stat follows symlinks; Lstat tells a dangling link from nothing at all
+			if linkInfo, lerr := os.Lstat(path); lerr == nil && linkInfo.Mode()&os.ModeSymlink != 0 {
+				return FolderBrokenSymlink
+			}
+			return FolderMissing
+		}
+		return FolderUnreadable
+	}
+
+	if !info.IsDir() {
+		return FolderNotDirectory
+	}
+
+	dir, err := os.Open(path)
+	if err != nil {
+		return FolderUnreadable
+	}
+	defer dir.Close()
+	if _, err := dir.Readdirnames(1); err != nil && !errors.Is(err, io.EOF) {
+		return FolderUnreadable
+	}
+
+	return FolderOK
+}
+
+// CanonicalPath returns the cleaned path with symlinks resolved, falling back
+// to the cleaned path when it cannot be resolved
+func (fs *Folders) CanonicalPath(path string) string {
+	cleaned := filepath.Clean(path)
+	if resolved, err := filepath.EvalSymlinks(cleaned); err == nil {
+		return resolved
+	}
+	return cleaned
+}
+
+// Problem is an issue found with a bookmark by Diagnose
+type Problem struct {
+	Bookmark *models.Bookmark
+	// Status is the folder status; FolderOK for duplicate and unclean entries
+	Status FolderStatus
+	// DuplicateOf is set when the bookmark points to the same folder as an
+	// older bookmark
+	DuplicateOf *models.Bookmark
+	// CleanPath is set when the stored folder differs from its cleaned form
+	// (e.g. a trailing slash) and is not a duplicate
+	CleanPath string
+}
+
+// Diagnose checks every bookmark's folder on disk and looks for bookmarks that
+// point to the same folder once trailing slashes and symlinks are resolved.
+// For each set of duplicates the bookmark with the lowest ID is kept.
+func (fs *Folders) Diagnose(bookmarks []*models.Bookmark) []Problem {
+	var problems []Problem
+
+	sorted := make([]*models.Bookmark, len(bookmarks))
+	copy(sorted, bookmarks)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+
+	first := make(map[string]*models.Bookmark, len(sorted))
+	for _, b := range sorted {
+		status := fs.CheckFolder(b.Folder)
+		if status != FolderOK {
+			problems = append(problems, Problem{Bookmark: b, Status: status})
+			continue
+		}
+
+		canonical := fs.CanonicalPath(b.Folder)
+		if original, ok := first[canonical]; ok {
+			problems = append(problems, Problem{Bookmark: b, Status: status, DuplicateOf: original})
+			continue
+		}
+		first[canonical] = b
+
+		if cleaned := filepath.Clean(b.Folder); cleaned != b.Folder {
+			problems = append(problems, Problem{Bookmark: b, Status: status, CleanPath: cleaned})
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jhoffmann/bookmark-manager/internal/models"
+)
+
+// ExportBookmark represents the JSON structure for exported bookmarks
+type ExportBookmark struct {
+	ID          uint     `json:"id"`
+	Folder      string   `json:"folder"`
+	Alias       string   `json:"alias,omitempty"`
+	Category    string   `json:"category"`
+	Tags        []string `json:"tags,omitempty"`
+	DateCreated string   `json:"date_created"`
+}
+
+// NewExportBookmark converts a bookmark to its export format
+func NewExportBookmark(b *models.Bookmark) ExportBookmark {
+	return ExportBookmark{
+		ID:          b.ID,
+		Folder:      b.Folder,
+		Alias:       b.Alias,
+		Category:    string(b.Category),
+		Tags:        b.TagNames(),
+		DateCreated: b.DateCreated.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+// WriteExport writes the bookmarks as an indented JSON array, the format read
+// by import
+func WriteExport(w io.Writer, bookmarks []*models.Bookmark) error {
+	exportBookmarks := make([]ExportBookmark, len(bookmarks))
+	for i, b := range bookmarks {
+		exportBookmarks[i] = NewExportBookmark(b)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ") // Pretty print
+	return encoder.Encode(exportBookmarks)
+}
+
+// ExportFile writes the bookmarks to a new file in the export format, failing
+// when the file exists. A leading ~ in the path is the user's home directory.
+func ExportFile(path string, bookmarks []*models.Bookmark) error {
+	if path == "" {
+		return fmt.Errorf("no file given")
+	}
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("failed to get user home directory: %w", err)
+		}
+		path = filepath.Join(home, path[1:])
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("%s already exists", path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+	if err := WriteExport(f, bookmarks); err != nil {
+		f.Close()
+		os.Remove(path)
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jhoffmann/bookmark-manager/internal/models"
+)
+
+func TestExportFile(t *testing.T) {
+	bookmarks := []*models.Bookmark{
+		{ID: 1, Folder: "/test/a", Category: "work", Alias: "a"},
+		{ID: 2, Folder: "/test/b"},
+	}
+
+	path := filepath.Join(t.TempDir(), "export.json")
+	if err := ExportFile(path, bookmarks); err != nil {
+		t.Fatalf("ExportFile() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	var exported []ExportBookmark
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if len(exported) != 2 || exported[0].Folder != "/test/a" || exported[0].Alias != "a" || exported[1].Folder != "/test/b" {
+		t.Errorf("exported = %+v, want both bookmarks", exported)
+	}
+
+	// An existing file is left alone
+	if err := ExportFile(path, bookmarks[1:]); err == nil {
+		t.Error("ExportFile() expected error for an existing file")
+	}
+	if again, _ := os.ReadFile(path); string(again) != string(data) {
+		t.Error("ExportFile() changed the existing file")
+	}
+
+	if err := ExportFile("", bookmarks); err == nil {
+		t.Error("ExportFile() expected error without a path")
+	}
+	if err := ExportFile(filepath.Join(t.TempDir(), "missing", "export.json"), bookmarks); err == nil {
+		t.Error("ExportFile() expected error for a missing directory")
+	}
+}
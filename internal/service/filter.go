@@ -0,0 +1,90 @@
+package service
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/jhoffmann/bookmark-manager/internal/fuzzy"
+	"github.com/jhoffmann/bookmark-manager/internal/models"
+)
+
+// FilterMatch is a bookmark matched by FuzzyFilter, with the positions of the
+// matched runes for highlighting
+type FilterMatch struct {
+	Bookmark *models.Bookmark
+	Score    int
+	// FolderPositions index the runes of Bookmark.Folder
+	FolderPositions []int
+	// TagPositions index the runes of the tag names joined by TagSeparator
+	TagPositions []int
+	// AliasPositions index the runes of Bookmark.Alias
+	AliasPositions []int
+}
+
+// TagSeparator joins tag names in the text searched by FuzzyFilter
+const TagSeparator = ", "
+
+// FuzzyFilter fuzzy-matches the query against the folder, tags and alias of
+// each bookmark and returns the matching ones, best first. The query is split
+// on whitespace and every term must match the folder, the tags or the alias;
+// terms starting with models.AliasPrefix only match the alias. Bookmarks with
+// equal scores keep their original order, and an empty query matches every
+// bookmark.
+func FuzzyFilter(bookmarks []*models.Bookmark, query string) []FilterMatch {
+	terms := strings.Fields(query)
+	matches := make([]FilterMatch, 0, len(bookmarks))
+
+	for _, b := range bookmarks {
+		match, ok := fuzzyMatchBookmark(b, terms)
+		if ok {
+			matches = append(matches, match)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+
+	return matches
+}
+
+// fuzzyMatchBookmark matches every term against the folder, tags and alias,
+// keeping the best scoring of the three, and sums the scores
+func fuzzyMatchBookmark(b *models.Bookmark, terms []string) (FilterMatch, bool) {
+	match := FilterMatch{Bookmark: b}
+	tags := strings.Join(b.TagNames(), TagSeparator)
+
+	for _, term := range terms {
+		if alias, ok := strings.CutPrefix(term, models.AliasPrefix); ok {
+			result, ok := fuzzy.Match(alias, b.Alias)
+			if !ok || b.Alias == "" {
+				return FilterMatch{}, false
+			}
+			match.Score += result.Score
+			match.AliasPositions = append(match.AliasPositions, result.Positions...)
+			continue
+		}
+
+		best := -1
+		var bestResult fuzzy.Result
+		for field, text := range []string{b.Folder, tags, b.Alias} {
+			if result, ok := fuzzy.Match(term, text); ok && (best < 0 || result.Score > bestResult.Score) {
+				best, bestResult = field, result
+			}
+		}
+
+		switch best {
+		case 0:
+			match.FolderPositions = append(match.FolderPositions, bestResult.Positions...)
+		case 1:
+			match.TagPositions = append(match.TagPositions, bestResult.Positions...)
+		case 2:
+			match.AliasPositions = append(match.AliasPositions, bestResult.Positions...)
+		default:
+			return FilterMatch{}, false
+		}
+		match.Score += bestResult.Score
+	}
+
+	return match, true
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/jhoffmann/bookmark-manager/internal/models"
+)
+
+func TestFuzzyFilter(t *testing.T) {
+	bookmark := func(folder string, tags ...string) *models.Bookmark {
+		b := &models.Bookmark{Folder: folder}
+		b.SetTagNames(tags)
+		return b
+	}
+	bookmarks := []*models.Bookmark{
+		bookmark("/home/user/src/submarine", "fun"),
+		bookmark("/home/user/src/bookmark-manager", "go", "tools"),
+		bookmark("/home/user/work/api", "work"),
+		bookmark("/home/user/notes"),
+	}
+	bookmarks[3].Alias = "journal"
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{
+			name:  "empty query keeps order",
+			query: "",
+			want: []string{
+				"/home/user/src/submarine",
+				"/home/user/src/bookmark-manager",
+				"/home/user/work/api",
+				"/home/user/notes",
+			},
+		},
+		{
+			name:  "abbreviation ranks boundary matches first",
+			query: "bm",
+			want:  []string{"/home/user/src/bookmark-manager", "/home/user/src/submarine"},
+		},
+		{
+			name:  "abbreviation across words",
+			query: "bmgr",
+			want:  []string{"/home/user/src/bookmark-manager"},
+		},
+		{
+			name:  "terms may match tags",
+			query: "api work",
+			want:  []string{"/home/user/work/api"},
+		},
+		{
+			name:  "terms may match aliases",
+			query: "jrnl",
+			want:  []string{"/home/user/notes"},
+		},
+		{
+			name:  "prefixed terms only match aliases",
+			query: "@user",
+			want:  nil,
+		},
+		{
+			name:  "prefix alone matches every alias",
+			query: "@",
+			want:  []string{"/home/user/notes"},
+		},
+		{
+			name:  "every term must match",
+			query: "notes go",
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := FuzzyFilter(bookmarks, tt.query)
+			var got []string
+			for _, m := range matches {
+				got = append(got, m.Bookmark.Folder)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("FuzzyFilter(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("FuzzyFilter(%q) = %v, want %v", tt.query, got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestFuzzyFilter_Positions(t *testing.T) {
+	b := &models.Bookmark{Folder: "/src/api"}
+	b.SetTagNames([]string{"work", "go"})
+
+	matches := FuzzyFilter([]*models.Bookmark{b}, "api go")
+	if len(matches) != 1 {
+		t.Fatalf("Expected 1 match, got %d", len(matches))
+	}
+	m := matches[0]
+	if len(m.FolderPositions) != 3 || m.FolderPositions[0] != 5 {
+		t.Errorf("FolderPositions = %v, want [5 6 7]", m.FolderPositions)
+	}
+	// "go" sits after "work, " in the joined tags
+	if len(m.TagPositions) != 2 || m.TagPositions[0] != 6 {
+		t.Errorf("TagPositions = %v, want [6 7]", m.TagPositions)
+	}
+}
+
+func TestFuzzyFilter_AliasPositions(t *testing.T) {
+	b := &models.Bookmark{Folder: "/src/api", Alias: "backend"}
+
+	matches := FuzzyFilter([]*models.Bookmark{b}, "@bend")
+	if len(matches) != 1 {
+		t.Fatalf("Expected 1 match, got %d", len(matches))
+	}
+	if m := matches[0]; len(m.AliasPositions) != 4 || m.AliasPositions[0] != 0 || len(m.FolderPositions) != 0 {
+		t.Errorf("AliasPositions = %v, FolderPositions = %v, want [0 4 5 6] and none", m.AliasPositions, m.FolderPositions)
+	}
+}
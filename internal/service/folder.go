@@ -5,12 +5,15 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
+	"strings"
 )
 
 // Folders handles folder operations like opening folders in the system file manager.
 type Folders struct {
 	platform string
+	opener   []string // Command replacing the system file manager, see SetOpener
 }
 
 // NewFolders creates a new Folders service instance.
@@ -21,15 +24,25 @@ func NewFolders() *Folders {
 	}
 }
 
-// OpenInFileManager opens the specified folder path in the system's file manager.
+// SetOpener sets the command folders are opened with instead of the system
+// file manager. A "{}" argument is replaced by the folder, which is appended
+// to the command otherwise. An empty command restores the file manager.
+func (fs *Folders) SetOpener(opener []string) {
+	fs.opener = opener
+}
+
+// OpenInFileManager opens the specified folder path in the configured opener or
+// the system's file manager.
 // Supports macOS (open), Windows (explorer), and Linux/Unix (xdg-open).
 func (fs *Folders) OpenInFileManager(path string) error {
 	var cmd *exec.Cmd
 
-	switch fs.platform {
-	case "darwin":
+	switch {
+	case len(fs.opener) > 0:
+		cmd = exec.Command(fs.opener[0], openerArgs(fs.opener[1:], path)...)
+	case fs.platform == "darwin":
 		cmd = exec.Command("open", path)
-	case "windows":
+	case fs.platform == "windows":
 		cmd = exec.Command("explorer", path)
 	default: // linux and others
 		cmd = exec.Command("xdg-open", path)
@@ -42,6 +55,24 @@ func (fs *Folders) OpenInFileManager(path string) error {
 	return nil
 }
 
+// openerArgs builds the arguments of the opener command, replacing "{}" with
+// the folder or appending it when there is no placeholder
+func openerArgs(args []string, path string) []string {
+	result := make([]string, 0, len(args)+1)
+	replaced := false
+	for _, arg := range args {
+		if strings.Contains(arg, "{}") {
+			arg = strings.ReplaceAll(arg, "{}", path)
+			replaced = true
+		}
+		result = append(result, arg)
+	}
+	if !replaced {
+		result = append(result, path)
+	}
+	return result
+}
+
 // WriteCwdFile writes the given path to a file for shell integration.
 // This is used when the application is invoked with --cwd-file flag.
 func (fs *Folders) WriteCwdFile(filePath, directoryPath string) error {
@@ -50,3 +81,110 @@ func (fs *Folders) WriteCwdFile(filePath, directoryPath string) error {
 	}
 	return nil
 }
+
+// DefaultProjectMarkers are the files or directories that mark a project root
+// when scanning for folders to bookmark
+var DefaultProjectMarkers = []string{".git", "go.mod", "package.json"}
+
+// skippedScanDirs are directory names never descended into while scanning
+var skippedScanDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+}
+
+// ScanForProjects walks root up to depth levels deep and returns every
+// directory containing one of the markers (glob patterns such as "*.sln" are
+// allowed). Matched directories are not descended into, and hidden, vendor and
+// node_modules directories are skipped.
+func (fs *Folders) ScanForProjects(root string, depth int, markers []string) ([]string, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %q: %w", root, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("failed to scan %q: not a directory", root)
+	}
+
+	for _, marker := range markers {
+		if _, err := filepath.Match(marker, ""); err != nil {
+			return nil, fmt.Errorf("invalid marker %q: %w", marker, err)
+		}
+	}
+
+	var found []string
+	err = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			// Unreadable directories are skipped rather than aborting the scan
+			if d != nil && d.IsDir() && path != root {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+
+		if path != root {
+			name := d.Name()
+			if strings.HasPrefix(name, ".") || skippedScanDirs[name] {
+				return filepath.SkipDir
+			}
+		}
+
+		if hasMarker(path, markers) {
+			found = append(found, path)
+			return filepath.SkipDir
+		}
+
+		level := 0
+		if rel, _ := filepath.Rel(root, path); rel != "." {
+			level = strings.Count(rel, string(filepath.Separator)) + 1
+		}
+		if level >= depth {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %q: %w", root, err)
+	}
+
+	return found, nil
+}
+
+// hasMarker reports whether the directory contains one of the markers. Only
+// the markers are patterns: the directory is not, whatever characters its path
+// contains.
+func hasMarker(dir string, markers []string) bool {
+	var names []string
+	for _, marker := range markers {
+		if !strings.ContainsAny(marker, "*?[") {
+			if _, err := os.Lstat(filepath.Join(dir, marker)); err == nil {
+				return true
+			}
+			continue
+		}
+
+		if names == nil {
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				continue
+			}
+			names = make([]string, len(entries))
+			for i, entry := range entries {
+				names[i] = entry.Name()
+			}
+		}
+		for _, name := range names {
+			if matched, _ := filepath.Match(marker, name); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -4,7 +4,11 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
+	"strings"
 	"testing"
+
+	"github.com/jhoffmann/bookmark-manager/internal/models"
 )
 
 func TestNewFolders(t *testing.T) {
@@ -111,3 +115,209 @@ func TestFolders_OpenInFileManager_Platform(t *testing.T) {
 		})
 	}
 }
+
+func TestOpenerArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"appended", []string{"-n"}, []string{"-n", "/src/api"}},
+		{"placeholder", []string{"--folder-uri={}", "-n"}, []string{"--folder-uri=/src/api", "-n"}},
+		{"no arguments", nil, []string{"/src/api"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := openerArgs(tt.args, "/src/api")
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("openerArgs(%q) = %q, want %q", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFolders_OpenInFileManager_Opener(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("relies on the true and false commands")
+	}
+
+	fs := NewFolders()
+	fs.SetOpener([]string{"true"})
+	if err := fs.OpenInFileManager(t.TempDir()); err != nil {
+		t.Errorf("OpenInFileManager() with a succeeding opener error = %v", err)
+	}
+
+	fs.SetOpener([]string{"false"})
+	if err := fs.OpenInFileManager(t.TempDir()); err == nil {
+		t.Error("OpenInFileManager() with a failing opener should return an error")
+	}
+}
+
+func TestFolders_CheckFolder(t *testing.T) {
+	fs := NewFolders()
+	tempDir := t.TempDir()
+
+	file := filepath.Join(tempDir, "file.txt")
+	if err := os.WriteFile(file, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	dangling := filepath.Join(tempDir, "dangling")
+	if err := os.Symlink(filepath.Join(tempDir, "nowhere"), dangling); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want FolderStatus
+	}{
+		{"existing folder", tempDir, FolderOK},
+		{"missing folder", filepath.Join(tempDir, "missing"), FolderMissing},
+		{"regular file", file, FolderNotDirectory},
+		{"broken symlink", dangling, FolderBrokenSymlink},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fs.CheckFolder(tt.path); got != tt.want {
+				t.Errorf("CheckFolder(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFolders_Diagnose(t *testing.T) {
+	fs := NewFolders()
+	tempDir := t.TempDir()
+
+	project := filepath.Join(tempDir, "project")
+	other := filepath.Join(tempDir, "other")
+	for _, dir := range []string{project, other} {
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	link := filepath.Join(tempDir, "link")
+	if err := os.Symlink(project, link); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	bookmarks := []*models.Bookmark{
+		{ID: 1, Folder: project},
+		{ID: 2, Folder: project + "/"},
+		{ID: 3, Folder: link},
+		{ID: 4, Folder: other + "/"},
+		{ID: 5, Folder: filepath.Join(tempDir, "gone")},
+	}
+
+	problems := fs.Diagnose(bookmarks)
+	byID := make(map[uint]Problem, len(problems))
+	for _, p := range problems {
+		byID[p.Bookmark.ID] = p
+	}
+
+	if len(problems) != 4 {
+		t.Errorf("Expected 4 problems, got %d", len(problems))
+	}
+	if _, ok := byID[1]; ok {
+		t.Error("Expected the oldest bookmark to be kept")
+	}
+	for _, id := range []uint{2, 3} {
+		if p := byID[id]; p.DuplicateOf == nil || p.DuplicateOf.ID != 1 {
+			t.Errorf("Expected bookmark %d to be a duplicate of 1, got %+v", id, p)
+		}
+	}
+	if p := byID[4]; p.CleanPath != other {
+		t.Errorf("Expected bookmark 4 to be relinked to %s, got %+v", other, p)
+	}
+	if p := byID[5]; p.Status != FolderMissing {
+		t.Errorf("Expected bookmark 5 to be missing, got %+v", p)
+	}
+}
+
+func TestFolders_ScanForProjects(t *testing.T) {
+	fs := NewFolders()
+	root := t.TempDir()
+
+	dirs := []string{
+		"work/api/.git",
+		"work/api/nested/.git", // inside a match, not descended into
+		"work/web",
+		"go/tool",
+		"deep/a/b/c/.git", // beyond depth
+		".hidden/repo/.git",
+		"node_modules/pkg/.git",
+	}
+	for _, dir := range dirs {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := []string{"work/web/package.json", "go/tool/go.mod"}
+	for _, file := range files {
+		if err := os.WriteFile(filepath.Join(root, file), []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	found, err := fs.ScanForProjects(root, 3, DefaultProjectMarkers)
+	if err != nil {
+		t.Fatalf("ScanForProjects() error = %v", err)
+	}
+
+	want := []string{
+		filepath.Join(root, "go/tool"),
+		filepath.Join(root, "work/api"),
+		filepath.Join(root, "work/web"),
+	}
+	if len(found) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, found)
+	}
+	for i := range want {
+		if found[i] != want[i] {
+			t.Errorf("Expected %s, got %s", want[i], found[i])
+		}
+	}
+
+	// Only the requested marker counts
+	found, err = fs.ScanForProjects(root, 3, []string{"go.mod"})
+	if err != nil {
+		t.Fatalf("ScanForProjects() error = %v", err)
+	}
+	if len(found) != 1 || found[0] != filepath.Join(root, "go/tool") {
+		t.Errorf("Expected only go/tool, got %v", found)
+	}
+
+	if _, err := fs.ScanForProjects(filepath.Join(root, "missing"), 3, DefaultProjectMarkers); err == nil {
+		t.Error("ScanForProjects() expected error for missing root")
+	}
+	if _, err := fs.ScanForProjects(root, 3, []string{"[.sln"}); err == nil {
+		t.Error("ScanForProjects() expected error for an invalid marker")
+	}
+}
+
+func TestFolders_ScanForProjects_PatternCharacters(t *testing.T) {
+	fs := NewFolders()
+	root := t.TempDir()
+
+	// Directory names are never patterns, for literal and glob markers alike
+	for _, file := range []string{"[a]/go.mod", "b*/app.sln", "a/go.mod"} {
+		path := filepath.Join(root, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	found, err := fs.ScanForProjects(root, 1, []string{"go.mod", "*.sln"})
+	if err != nil {
+		t.Fatalf("ScanForProjects() error = %v", err)
+	}
+	want := []string{filepath.Join(root, "[a]"), filepath.Join(root, "a"), filepath.Join(root, "b*")}
+	if !slices.Equal(found, want) {
+		t.Errorf("ScanForProjects() = %v, want %v", found, want)
+	}
+}
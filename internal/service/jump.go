@@ -0,0 +1,126 @@
+package service
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jhoffmann/bookmark-manager/internal/models"
+)
+
+// Match is a bookmark scored against a set of query terms
+type Match struct {
+	Bookmark *models.Bookmark
+	Score    float64
+}
+
+// RankMatches scores bookmarks against the query terms and returns the matching
+// ones, best first. Every term must match the folder path or a category (any
+// tag); blank terms are ignored, and without any other term nothing matches.
+// Matches against the basename weigh more than matches against other path
+// segments, which in turn weigh more than matches against categories; frecency
+// is added on top so frequently used folders win ties.
+func RankMatches(bookmarks []*models.Bookmark, terms []string, now time.Time) []Match {
+	var matches []Match
+
+	for _, b := range bookmarks {
+		score, ok := scoreBookmark(b, terms)
+		if !ok {
+			continue
+		}
+		matches = append(matches, Match{
+			Bookmark: b,
+			Score:    score + b.Frecency(now),
+		})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		// Prefer shallower paths on equal scores
+		return len(matches[i].Bookmark.Folder) < len(matches[j].Bookmark.Folder)
+	})
+
+	return matches
+}
+
+// scoreBookmark returns the match score of a bookmark and whether every term
+// matched, which needs at least one term that is not blank
+func scoreBookmark(b *models.Bookmark, terms []string) (float64, bool) {
+	folder := strings.ToLower(filepath.ToSlash(b.Folder))
+	base := strings.ToLower(filepath.Base(b.Folder))
+	categories := make([]string, len(b.Tags))
+	for i, tag := range b.Tags {
+		categories[i] = strings.ToLower(tag.Name)
+	}
+	segments := strings.Split(strings.Trim(folder, "/"), "/")
+
+	var total float64
+	matched := false
+	for i, term := range terms {
+		term = strings.ToLower(strings.TrimSpace(term))
+		if term == "" {
+			continue
+		}
+		matched = true
+
+		score := scoreTerm(term, folder, base, segments, categories)
+		if score == 0 {
+			return 0, false
+		}
+
+		// Like zoxide, the last term is expected to describe the target
+		// directory itself, so a basename hit on it counts double
+		if i == len(terms)-1 && strings.Contains(base, term) {
+			score *= 2
+		}
+		total += score
+	}
+
+	return total, matched
+}
+
+// scoreTerm returns the best score for a single term, or 0 if it does not match
+func scoreTerm(term, folder, base string, segments []string, categories []string) float64 {
+	switch {
+	case base == term:
+		return 100
+	case strings.HasPrefix(base, term):
+		return 60
+	case strings.Contains(base, term):
+		return 40
+	}
+
+	var best float64
+	for _, segment := range segments {
+		switch {
+		case segment == term:
+			best = max(best, 30)
+		case strings.HasPrefix(segment, term):
+			best = max(best, 20)
+		case strings.Contains(segment, term):
+			best = max(best, 10)
+		}
+	}
+	if best > 0 {
+		return best
+	}
+
+	// Terms spanning several segments, such as "src/api"
+	if strings.Contains(folder, term) {
+		return 15
+	}
+
+	for _, category := range categories {
+		switch {
+		case category == term:
+			best = max(best, 25)
+		case strings.Contains(category, term):
+			best = max(best, 10)
+		}
+	}
+
+	return best
+}
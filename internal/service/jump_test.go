@@ -0,0 +1,98 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jhoffmann/bookmark-manager/internal/models"
+)
+
+func TestRankMatches(t *testing.T) {
+	now := time.Now()
+	bookmarks := []*models.Bookmark{
+		{Folder: "/home/user/src/api-gateway", Category: "work"},
+		{Folder: "/home/user/src/api", Category: "work"},
+		{Folder: "/home/user/api/docs", Category: "personal"},
+		{Folder: "/home/user/notes", Category: "api"},
+		{Folder: "/home/user/music", Category: "fun"},
+	}
+	// Saved bookmarks carry their category as a tag
+	for _, b := range bookmarks {
+		b.SetTagNames([]string{string(b.Category)})
+	}
+	bookmarks[4].SetTagNames([]string{"fun", "guitar"})
+
+	tests := []struct {
+		name  string
+		terms []string
+		want  []string
+	}{
+		{
+			name:  "basename beats segment and category",
+			terms: []string{"api"},
+			want: []string{
+				"/home/user/src/api",
+				"/home/user/src/api-gateway",
+				"/home/user/api/docs",
+				"/home/user/notes",
+			},
+		},
+		{
+			name:  "all terms must match",
+			terms: []string{"work", "gateway"},
+			want:  []string{"/home/user/src/api-gateway"},
+		},
+		{
+			name:  "any tag",
+			terms: []string{"guitar"},
+			want:  []string{"/home/user/music"},
+		},
+		{
+			name:  "multi-segment term",
+			terms: []string{"api/docs"},
+			want:  []string{"/home/user/api/docs"},
+		},
+		{
+			name:  "case insensitive",
+			terms: []string{"MUSIC"},
+			want:  []string{"/home/user/music"},
+		},
+		{
+			name:  "no match",
+			terms: []string{"nothing"},
+			want:  nil,
+		},
+		{
+			name:  "blank terms",
+			terms: []string{"", " "},
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := RankMatches(bookmarks, tt.terms, now)
+			if len(matches) != len(tt.want) {
+				t.Fatalf("Expected %d matches, got %d", len(tt.want), len(matches))
+			}
+			for i, folder := range tt.want {
+				if matches[i].Bookmark.Folder != folder {
+					t.Errorf("Position %d: expected %s, got %s", i, folder, matches[i].Bookmark.Folder)
+				}
+			}
+		})
+	}
+}
+
+func TestRankMatches_FrecencyBreaksTies(t *testing.T) {
+	now := time.Now()
+	bookmarks := []*models.Bookmark{
+		{Folder: "/a/project"},
+		{Folder: "/b/project", VisitCount: 5, LastVisited: &now},
+	}
+
+	matches := RankMatches(bookmarks, []string{"project"}, now)
+	if len(matches) != 2 || matches[0].Bookmark.Folder != "/b/project" {
+		t.Errorf("Expected frequently visited /b/project first, got %v", matches)
+	}
+}
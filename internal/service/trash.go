@@ -0,0 +1,156 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jhoffmann/bookmark-manager/internal/models"
+	"gorm.io/gorm"
+)
+
+// ListDeleted retrieves all soft-deleted bookmarks, most recently deleted first
+func (s *Bookmarks) ListDeleted() ([]*models.Bookmark, error) {
+	gormDB := s.db.GetDB()
+	if gormDB == nil {
+		return nil, fmt.Errorf("database connection is not available")
+	}
+
+	var bookmarks []*models.Bookmark
+	if err := gormDB.Unscoped().Preload("Tags").
+		Where("deleted_at IS NOT NULL").
+		Order("deleted_at DESC, folder").
+		Find(&bookmarks).Error; err != nil {
+		return nil, fmt.Errorf("failed to list deleted bookmarks: %w", err)
+	}
+
+	return bookmarks, nil
+}
+
+// Restore moves a soft-deleted bookmark out of the trash. It fails if the
+// folder has been bookmarked again in the meantime.
+func (s *Bookmarks) Restore(id uint) (*models.Bookmark, error) {
+	gormDB := s.db.GetDB()
+	if gormDB == nil {
+		return nil, fmt.Errorf("database connection is not available")
+	}
+
+	var bookmark *models.Bookmark
+	err := gormDB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		bookmark, err = s.restore(tx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return bookmark, nil
+}
+
+// restore moves a bookmark out of the trash within the given transaction
+func (s *Bookmarks) restore(tx *gorm.DB, id uint) (*models.Bookmark, error) {
+	bookmark, err := s.getDeleted(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var active int64
+	if err := tx.Model(&models.Bookmark{}).Where("folder = ?", bookmark.Folder).Count(&active).Error; err != nil {
+		return nil, fmt.Errorf("failed to check for existing bookmark: %w", err)
+	}
+	if active > 0 {
+		return nil, fmt.Errorf("cannot restore bookmark %d: %s is already bookmarked", id, bookmark.Folder)
+	}
+	if err := checkAliasAvailable(tx, bookmark); err != nil {
+		return nil, fmt.Errorf("cannot restore bookmark %d: %w", id, err)
+	}
+
+	if err := tx.Unscoped().Model(bookmark).Update("deleted_at", nil).Error; err != nil {
+		return nil, fmt.Errorf("failed to restore bookmark: %w", err)
+	}
+	bookmark.DeletedAt = gorm.DeletedAt{}
+
+	return bookmark, nil
+}
+
+// PurgeByID permanently deletes a single bookmark from the trash
+func (s *Bookmarks) PurgeByID(id uint) error {
+	gormDB := s.db.GetDB()
+	if gormDB == nil {
+		return fmt.Errorf("database connection is not available")
+	}
+
+	if _, err := s.getDeleted(gormDB, id); err != nil {
+		return err
+	}
+
+	return gormDB.Transaction(func(tx *gorm.DB) error {
+		return purgeWhere(tx, "id = ?", id)
+	})
+}
+
+// Purge permanently deletes trashed bookmarks that were deleted more than
+// olderThan ago, or all trashed bookmarks when olderThan is zero. It returns the
+// number of bookmarks removed.
+func (s *Bookmarks) Purge(olderThan time.Duration) (int64, error) {
+	gormDB := s.db.GetDB()
+	if gormDB == nil {
+		return 0, fmt.Errorf("database connection is not available")
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+
+	var count int64
+	err := gormDB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&models.Bookmark{}).
+			Where("deleted_at IS NOT NULL AND deleted_at <= ?", cutoff).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		return purgeWhere(tx, "deleted_at <= ?", cutoff)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge bookmarks: %w", err)
+	}
+
+	return count, nil
+}
+
+// getDeleted retrieves a bookmark by ID, requiring it to be in the trash
+func (s *Bookmarks) getDeleted(gormDB *gorm.DB, id uint) (*models.Bookmark, error) {
+	var bookmark models.Bookmark
+	if err := gormDB.Unscoped().Preload("Tags").First(&bookmark, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("bookmark with ID %d %w", id, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get bookmark: %w", err)
+	}
+
+	if !bookmark.DeletedAt.Valid {
+		return nil, fmt.Errorf("bookmark with ID %d is not in the trash", id)
+	}
+
+	return &bookmark, nil
+}
+
+// purgeWhere permanently deletes trashed bookmarks matching the condition,
+// along with their tag associations
+func purgeWhere(tx *gorm.DB, query string, args ...interface{}) error {
+	trashed := tx.Unscoped().Model(&models.Bookmark{}).
+		Select("id").
+		Where("deleted_at IS NOT NULL").
+		Where(query, args...)
+
+	if err := tx.Exec("DELETE FROM bookmark_tags WHERE bookmark_id IN (?)", trashed).Error; err != nil {
+		return fmt.Errorf("failed to delete bookmark tags: %w", err)
+	}
+
+	if err := tx.Unscoped().
+		Where("deleted_at IS NOT NULL").
+		Where(query, args...).
+		Delete(&models.Bookmark{}).Error; err != nil {
+		return fmt.Errorf("failed to delete bookmarks: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jhoffmann/bookmark-manager/internal/models"
+)
+
+func TestBookmarks_Trash(t *testing.T) {
+	s := newTestBookmarks(t)
+
+	keep := &models.Bookmark{Folder: "/test/keep", Category: "work"}
+	trashed := &models.Bookmark{Folder: "/test/trashed", Category: "work"}
+	for _, b := range []*models.Bookmark{keep, trashed} {
+		if err := s.Save(b); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	if err := s.Delete(trashed); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	deleted, err := s.ListDeleted()
+	if err != nil {
+		t.Fatalf("ListDeleted() error = %v", err)
+	}
+	if len(deleted) != 1 || deleted[0].ID != trashed.ID {
+		t.Fatalf("Expected trashed bookmark in trash, got %v", deleted)
+	}
+	if !deleted[0].HasTag("work") {
+		t.Errorf("Expected trashed bookmark to keep its tags, got %v", deleted[0].TagNames())
+	}
+
+	if _, err := s.Restore(keep.ID); err == nil {
+		t.Error("Restore() expected error for bookmark not in trash")
+	}
+
+	restored, err := s.Restore(trashed.ID)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if restored.DeletedAt.Valid {
+		t.Error("Expected restored bookmark to have no deletion time")
+	}
+
+	all, err := s.List(0, 0)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("Expected 2 bookmarks after restore, got %d", len(all))
+	}
+}
+
+func TestBookmarks_Restore_FolderBookmarkedAgain(t *testing.T) {
+	s := newTestBookmarks(t)
+
+	old := &models.Bookmark{Folder: "/test/again"}
+	if err := s.Save(old); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := s.Delete(old); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := s.Save(&models.Bookmark{Folder: "/test/again"}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if _, err := s.Restore(old.ID); err == nil {
+		t.Error("Restore() expected error when folder is bookmarked again")
+	}
+}
+
+func TestBookmarks_Purge(t *testing.T) {
+	s := newTestBookmarks(t)
+
+	folders := []string{"/test/a", "/test/b", "/test/c"}
+	bookmarks := make([]*models.Bookmark, len(folders))
+	for i, folder := range folders {
+		bookmarks[i] = &models.Bookmark{Folder: folder, Category: "work"}
+		if err := s.Save(bookmarks[i]); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+	for _, b := range bookmarks[:2] {
+		if err := s.Delete(b); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+	}
+
+	// Nothing was deleted an hour ago
+	count, err := s.Purge(time.Hour)
+	if err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected 0 purged, got %d", count)
+	}
+
+	if err := s.PurgeByID(bookmarks[2].ID); err == nil {
+		t.Error("PurgeByID() expected error for bookmark not in trash")
+	}
+	if err := s.PurgeByID(bookmarks[0].ID); err != nil {
+		t.Fatalf("PurgeByID() error = %v", err)
+	}
+
+	count, err = s.Purge(0)
+	if err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 purged, got %d", count)
+	}
+
+	deleted, err := s.ListDeleted()
+	if err != nil {
+		t.Fatalf("ListDeleted() error = %v", err)
+	}
+	if len(deleted) != 0 {
+		t.Errorf("Expected empty trash, got %d bookmarks", len(deleted))
+	}
+
+	var links int64
+	s.db.GetDB().Table("bookmark_tags").Count(&links)
+	if links != 1 {
+		t.Errorf("Expected only the active bookmark's tag link to remain, got %d", links)
+	}
+}
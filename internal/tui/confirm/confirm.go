@@ -2,14 +2,24 @@
 package confirm
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jhoffmann/bookmark-manager/internal/models"
+	"github.com/jhoffmann/bookmark-manager/internal/tui/keys"
+	"github.com/jhoffmann/bookmark-manager/internal/tui/styles"
 )
 
 var docStyle = lipgloss.NewStyle().Margin(1, 2)
 
+// previewLimit is the number of folders listed when several bookmarks are
+// affected
+const previewLimit = 5
+
 // choiceItem implements list.Item for yes/no choices
 type choiceItem struct {
 	title       string
@@ -21,55 +31,148 @@ func (i choiceItem) FilterValue() string { return i.title }
 func (i choiceItem) Title() string       { return i.title }
 func (i choiceItem) Description() string { return i.description }
 
+// keyMap defines the key bindings of the confirmation
+type keyMap struct {
+	Select key.Binding
+	Yes    key.Binding
+	No     key.Binding
+}
+
 // Model represents the confirmation state
 type Model struct {
-	list     list.Model
-	bookmark *models.Bookmark
-	visible  bool
-	result   bool
-	chosen   bool
+	list      list.Model
+	keys      keyMap
+	bookmarks []*models.Bookmark
+	preview   string // Folders affected, shown when there are several
+	height    int    // Height available to the dialog
+	visible   bool
+	result    bool
+	chosen    bool
 }
 
 // New creates a new confirmation model
 func New() Model {
-	items := []list.Item{
-		choiceItem{title: "Yes", description: "Delete this bookmark", value: true},
-		choiceItem{title: "No", description: "Cancel - don't delete", value: false},
-	}
-
 	delegate := list.NewDefaultDelegate()
 	delegate.ShowDescription = true
+	delegate.Styles = styles.ItemStyles()
 
-	l := list.New(items, delegate, 0, 0) // Use same sizing as main list
+	l := list.New(choices(1), delegate, 0, 0) // Use same sizing as main list
 	l.Title = "Delete Bookmark?"
 	l.SetShowStatusBar(true) // Same as main list
 	l.SetFilteringEnabled(false)
+	styles.StyleList(&l)
+	// Quitting is left to the list behind the dialog
+	l.DisableQuitKeybindings()
 
-	return Model{
+	m := Model{
 		list:    l,
 		visible: false,
 		chosen:  false,
 	}
+	m.SetKeys(keys.Default().Confirm)
+	return m
+}
+
+// choices returns the yes and no items for a number of bookmarks
+func choices(count int) []list.Item {
+	yes := "Delete this bookmark"
+	if count > 1 {
+		yes = fmt.Sprintf("Delete these %d bookmarks", count)
+	}
+	return choicesFor(yes, "Cancel - don't delete")
+}
+
+// choicesFor returns the yes and no items described by what they do
+func choicesFor(yes, no string) []list.Item {
+	return []list.Item{
+		choiceItem{title: "Yes", description: yes, value: true},
+		choiceItem{title: "No", description: no, value: false},
+	}
+}
+
+// SetKeys replaces the key bindings of the confirmation
+func (m *Model) SetKeys(bindings keys.Map) {
+	m.keys = keyMap{
+		Select: bindings.Get("select"),
+		Yes:    bindings.Get("yes"),
+		No:     bindings.Get("no"),
+	}
+	m.list.KeyMap.CursorUp = bindings.Get("up")
+	m.list.KeyMap.CursorDown = bindings.Get("down")
+
+	km := m.keys
+	m.list.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{km.Select, km.Yes, km.No}
+	}
+}
+
+// Show displays the confirmation for the given bookmarks. A non-empty message
+// replaces the default "Delete: <folder>?" or "Delete <n> bookmarks?" title;
+// when there are several bookmarks, the first folders are listed below it.
+func (m *Model) Show(bookmarks []*models.Bookmark, message string) {
+	m.show(bookmarks, message, choices(len(bookmarks)))
+}
+
+// ShowAction displays the confirmation of another action than deleting the
+// given bookmarks, with a title and a description of what yes does
+func (m *Model) ShowAction(bookmarks []*models.Bookmark, title, action string) {
+	m.show(bookmarks, title, choicesFor(action, "Cancel"))
 }
 
-// Show displays the confirmation with the given bookmark
-func (m *Model) Show(bookmark *models.Bookmark, message string) {
-	m.bookmark = bookmark
+func (m *Model) show(bookmarks []*models.Bookmark, message string, items []list.Item) {
+	m.bookmarks = bookmarks
 	m.visible = true
 	m.chosen = false
 	m.result = false
-	m.list.Select(0) // Default to "No"
+	m.list.SetItems(items)
+	m.list.Select(1) // Default to "No"
 
 	// Update title to include bookmark info
-	if bookmark != nil {
-		m.list.Title = "Delete: " + bookmark.Folder + "?"
+	switch {
+	case message != "":
+		m.list.Title = message
+	case len(bookmarks) == 1:
+		m.list.Title = "Delete: " + bookmarks[0].Folder + "?"
+	default:
+		m.list.Title = fmt.Sprintf("Delete %d bookmarks?", len(bookmarks))
+	}
+
+	m.preview = ""
+	if len(bookmarks) > 1 {
+		m.preview = preview(bookmarks)
+	}
+	m.resize()
+}
+
+// preview lists the first folders of the bookmarks and how many more there are
+func preview(bookmarks []*models.Bookmark) string {
+	var lines []string
+	for i, b := range bookmarks {
+		if i == previewLimit {
+			lines = append(lines, fmt.Sprintf("… and %d more", len(bookmarks)-previewLimit))
+			break
+		}
+		lines = append(lines, b.Folder)
+	}
+	return styles.Hint.Render(strings.Join(lines, "\n"))
+}
+
+// resize fits the choices below the preview
+func (m *Model) resize() {
+	if m.height == 0 {
+		return
 	}
+	height := m.height
+	if m.preview != "" {
+		height -= lipgloss.Height(m.preview) + 2
+	}
+	m.list.SetHeight(max(height, 0))
 }
 
 // Hide hides the confirmation
 func (m *Model) Hide() {
 	m.visible = false
-	m.bookmark = nil
+	m.bookmarks = nil
 	m.chosen = false
 }
 
@@ -87,11 +190,13 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		h, v := docStyle.GetFrameSize()
-		m.list.SetSize(msg.Width-h, msg.Height-v-4)
+		m.list.SetWidth(msg.Width - h)
+		m.height = msg.Height - v - 4
+		m.resize()
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "enter":
+		switch {
+		case key.Matches(msg, m.keys.Select):
 			// Get selected choice
 			if selectedItem, ok := m.list.SelectedItem().(choiceItem); ok {
 				m.result = selectedItem.value
@@ -99,12 +204,12 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				m.visible = false
 				return m, nil
 			}
-		case "esc", "q", "n":
+		case key.Matches(msg, m.keys.No):
 			m.result = false
 			m.chosen = true
 			m.visible = false
 			return m, nil
-		case "y":
+		case key.Matches(msg, m.keys.Yes):
 			m.result = true
 			m.chosen = true
 			m.visible = false
@@ -122,20 +227,23 @@ func (m Model) View() string {
 	if !m.visible {
 		return ""
 	}
+	if m.preview != "" {
+		return docStyle.Render(m.list.View() + "\n\n" + m.preview)
+	}
 	return docStyle.Render(m.list.View())
 }
 
 // Result represents the result of the confirmation
 type Result struct {
 	Confirmed bool
-	Bookmark  *models.Bookmark
+	Bookmarks []*models.Bookmark
 }
 
 // GetResult returns the result based on current state
 func (m Model) GetResult() Result {
 	return Result{
 		Confirmed: m.result,
-		Bookmark:  m.bookmark,
+		Bookmarks: m.bookmarks,
 	}
 }
 
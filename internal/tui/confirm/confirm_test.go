@@ -0,0 +1,32 @@
+package confirm
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jhoffmann/bookmark-manager/internal/models"
+)
+
+func TestModel_DefaultsToNo(t *testing.T) {
+	bookmarks := []*models.Bookmark{{ID: 1, Folder: "/test/a"}}
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+
+	for name, show := range map[string]func(m *Model){
+		"delete": func(m *Model) { m.Show(bookmarks, "") },
+		"action": func(m *Model) { m.ShowAction(bookmarks, "Remove work?", "Remove it") },
+	} {
+		t.Run(name, func(t *testing.T) {
+			m := New()
+			m, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+			show(&m)
+
+			m, _ = m.Update(enter)
+			if !m.HasResult() {
+				t.Fatal("Expected enter to close the dialog")
+			}
+			if m.GetResult().Confirmed {
+				t.Error("Expected enter on a freshly shown dialog to cancel")
+			}
+		})
+	}
+}
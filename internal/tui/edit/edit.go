@@ -1,71 +1,161 @@
-// Package edit provides a text input interface for editing bookmark categories.
+// Package edit provides a text input interface for editing bookmark categories
+// and aliases, renaming categories and naming the file bookmarks are exported
+// to.
 package edit
 
 import (
+	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jhoffmann/bookmark-manager/internal/models"
+	"github.com/jhoffmann/bookmark-manager/internal/tui/keys"
+	"github.com/jhoffmann/bookmark-manager/internal/tui/styles"
 )
 
-var (
-	titleStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FAFAFA")).
-			Background(lipgloss.Color("#7D56F4")).
-			Padding(0, 1)
+var docStyle = lipgloss.NewStyle().Margin(1, 2)
 
-	docStyle = lipgloss.NewStyle().Margin(1, 2)
+// Field is the bookmark attribute being edited
+type Field int
+
+const (
+	// FieldCategory edits the bookmark's category
+	FieldCategory Field = iota
+	// FieldAlias edits the bookmark's alias
+	FieldAlias
+	// FieldExport asks for the file to export the bookmarks to
+	FieldExport
+	// FieldRename asks for the new name of a category
+	FieldRename
 )
 
-// Model represents the category editing state
+// DefaultExportFile is suggested as the file to export to
+const DefaultExportFile = "bookmarks.json"
+
+// String returns the display name of the field
+func (f Field) String() string {
+	switch f {
+	case FieldAlias:
+		return "Alias"
+	case FieldExport:
+		return "Export"
+	case FieldRename:
+		return "Rename Category"
+	}
+	return "Category"
+}
+
+// keyMap defines the key bindings of the edit dialog
+type keyMap struct {
+	Submit key.Binding
+	Cancel key.Binding
+}
+
+// Model represents the editing state
 type Model struct {
 	textInput textinput.Model
-	bookmark  *models.Bookmark
+	keys      keyMap
+	bookmarks []*models.Bookmark
+	category  string // Category being renamed
+	field     Field
 	visible   bool
 	result    string
 	submitted bool
 	cancelled bool
 }
 
-// New creates a new category edit model
+// New creates a new edit model
 func New() Model {
 	ti := textinput.New()
-	ti.Placeholder = "Enter category name..."
 	ti.Focus()
 	ti.CharLimit = 50
 	ti.Width = 30
+	styles.StyleInput(&ti)
 
 	return Model{
 		textInput: ti,
+		keys:      newKeyMap(keys.Default().Edit),
 		visible:   false,
 		submitted: false,
 		cancelled: false,
 	}
 }
 
-// Show displays the edit dialog with the given bookmark
-func (m *Model) Show(bookmark *models.Bookmark) {
-	m.bookmark = bookmark
+// newKeyMap picks the dialog's bindings from the effective ones
+func newKeyMap(bindings keys.Map) keyMap {
+	return keyMap{
+		Submit: bindings.Get("submit"),
+		Cancel: bindings.Get("cancel"),
+	}
+}
+
+// SetKeys replaces the key bindings of the edit dialog
+func (m *Model) SetKeys(bindings keys.Map) {
+	m.keys = newKeyMap(bindings)
+}
+
+// Show displays the edit dialog for a field of the given bookmarks. Aliases
+// are unique, so they are edited one bookmark at a time.
+func (m *Model) Show(bookmarks []*models.Bookmark, field Field) {
+	m.bookmarks = bookmarks
+	m.category = ""
+	m.field = field
 	m.visible = true
 	m.submitted = false
 	m.cancelled = false
 	m.result = ""
 
-	// Pre-populate with current category
-	currentCategory := string(bookmark.Category)
-	m.textInput.SetValue(currentCategory)
+	// Pre-populate with the current value
+	var current string
+	m.textInput.CharLimit = 50
+	switch field {
+	case FieldAlias:
+		current = bookmarks[0].Alias
+		m.textInput.Placeholder = "Enter alias (empty to remove)..."
+	case FieldExport:
+		current = DefaultExportFile
+		m.textInput.Placeholder = "Enter the file to write..."
+		m.textInput.CharLimit = 0
+	case FieldCategory:
+		current = sharedCategory(bookmarks)
+		m.textInput.Placeholder = "Enter category name..."
+	}
+	m.textInput.SetValue(current)
 	m.textInput.Focus()
 
 	// Position cursor at end so user can easily edit
 	m.textInput.CursorEnd()
 }
 
+// ShowRename displays the edit dialog for the new name of a category. Naming
+// it like another category merges the two.
+func (m *Model) ShowRename(category string) {
+	m.Show(nil, FieldRename)
+	m.category = category
+	m.textInput.Placeholder = "Enter the new name (or an existing category to merge)..."
+	m.textInput.SetValue(category)
+	m.textInput.CursorEnd()
+}
+
+// sharedCategory returns the category of the bookmarks when they all have the
+// same one
+func sharedCategory(bookmarks []*models.Bookmark) string {
+	category := bookmarks[0].Category
+	for _, b := range bookmarks[1:] {
+		if b.Category != category {
+			return ""
+		}
+	}
+	return string(category)
+}
+
 // Hide hides the edit dialog
 func (m *Model) Hide() {
 	m.visible = false
-	m.bookmark = nil
+	m.bookmarks = nil
 	m.submitted = false
 	m.cancelled = false
 	m.textInput.SetValue("")
@@ -84,23 +174,18 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "enter":
-			// Submit the new category
+		switch {
+		case key.Matches(msg, m.keys.Submit):
+			// Submit the new value
 			m.result = strings.TrimSpace(m.textInput.Value())
 			m.submitted = true
 			m.visible = false
 			return m, nil
-		case "esc":
+		case key.Matches(msg, m.keys.Cancel):
 			// Cancel editing
 			m.cancelled = true
 			m.visible = false
 			return m, nil
-		case "ctrl+c":
-			// Also cancel on ctrl+c
-			m.cancelled = true
-			m.visible = false
-			return m, nil
 		}
 	}
 
@@ -115,37 +200,54 @@ func (m Model) View() string {
 		return ""
 	}
 
-	title := "Edit Category"
-	if m.bookmark != nil {
-		title = "Edit Category: " + m.bookmark.Folder
-	}
-
 	content := lipgloss.JoinVertical(lipgloss.Left,
-		titleStyle.Render(title),
+		styles.Title.Render(m.title()),
 		"",
 		m.textInput.View(),
 		"",
-		"Press Enter to save, Esc to cancel",
+		styles.Hint.Render("Press "+m.keys.Submit.Help().Key+" to save, "+m.keys.Cancel.Help().Key+" to cancel"),
 	)
 
 	return docStyle.Render(content)
 }
 
-// Result represents the result of the category edit
+// title names the field and the bookmarks being edited
+func (m Model) title() string {
+	title := "Edit " + m.field.String()
+	switch m.field {
+	case FieldExport:
+		title = m.field.String()
+	case FieldRename:
+		return m.field.String() + ": " + m.category
+	}
+	switch len(m.bookmarks) {
+	case 0:
+		return title
+	case 1:
+		return title + ": " + m.bookmarks[0].Folder
+	}
+	return fmt.Sprintf("%s: %d bookmarks", title, len(m.bookmarks))
+}
+
+// Result represents the result of the edit
 type Result struct {
-	NewCategory string
-	Bookmark    *models.Bookmark
-	Submitted   bool
-	Cancelled   bool
+	Field     Field
+	Value     string
+	Bookmarks []*models.Bookmark
+	Category  string // Category renamed with FieldRename
+	Submitted bool
+	Cancelled bool
 }
 
 // GetResult returns the result based on current state
 func (m Model) GetResult() Result {
 	return Result{
-		NewCategory: m.result,
-		Bookmark:    m.bookmark,
-		Submitted:   m.submitted,
-		Cancelled:   m.cancelled,
+		Field:     m.field,
+		Value:     m.result,
+		Bookmarks: m.bookmarks,
+		Category:  m.category,
+		Submitted: m.submitted,
+		Cancelled: m.cancelled,
 	}
 }
 
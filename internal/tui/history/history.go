@@ -0,0 +1,64 @@
+// Package history provides an undo/redo stack for reversible TUI operations.
+package history
+
+// Entry is a reversible operation. Undo and Redo perform the actual work and
+// are expected to be run inside a tea.Cmd.
+type Entry struct {
+	Description string
+	Undo        func() error
+	Redo        func() error
+}
+
+// History holds the undo and redo stacks for a session
+type History struct {
+	undo  []Entry
+	redo  []Entry
+	limit int
+}
+
+// New creates a history that keeps at most limit undoable entries
+// (unlimited when limit is zero or negative)
+func New(limit int) History {
+	return History{limit: limit}
+}
+
+// Record adds a newly performed operation and clears the redo stack
+func (h *History) Record(e Entry) {
+	h.undo = append(h.undo, e)
+	if h.limit > 0 && len(h.undo) > h.limit {
+		h.undo = h.undo[len(h.undo)-h.limit:]
+	}
+	h.redo = nil
+}
+
+// Undo moves the most recent entry to the redo stack and returns it
+func (h *History) Undo() (Entry, bool) {
+	if len(h.undo) == 0 {
+		return Entry{}, false
+	}
+	e := h.undo[len(h.undo)-1]
+	h.undo = h.undo[:len(h.undo)-1]
+	h.redo = append(h.redo, e)
+	return e, true
+}
+
+// Redo moves the most recently undone entry back to the undo stack and returns it
+func (h *History) Redo() (Entry, bool) {
+	if len(h.redo) == 0 {
+		return Entry{}, false
+	}
+	e := h.redo[len(h.redo)-1]
+	h.redo = h.redo[:len(h.redo)-1]
+	h.undo = append(h.undo, e)
+	return e, true
+}
+
+// CanUndo reports whether there is anything to undo
+func (h History) CanUndo() bool {
+	return len(h.undo) > 0
+}
+
+// CanRedo reports whether there is anything to redo
+func (h History) CanRedo() bool {
+	return len(h.redo) > 0
+}
@@ -0,0 +1,66 @@
+package history
+
+import "testing"
+
+func TestHistory(t *testing.T) {
+	h := New(0)
+
+	if _, ok := h.Undo(); ok {
+		t.Error("Undo() on empty history should report false")
+	}
+
+	h.Record(Entry{Description: "first"})
+	h.Record(Entry{Description: "second"})
+
+	e, ok := h.Undo()
+	if !ok || e.Description != "second" {
+		t.Fatalf("Undo() = %q, %v; want second, true", e.Description, ok)
+	}
+	if !h.CanRedo() {
+		t.Error("Expected redo to be available after undo")
+	}
+
+	e, ok = h.Redo()
+	if !ok || e.Description != "second" {
+		t.Fatalf("Redo() = %q, %v; want second, true", e.Description, ok)
+	}
+
+	// Recording after an undo discards the redo stack
+	h.Undo()
+	h.Record(Entry{Description: "third"})
+	if h.CanRedo() {
+		t.Error("Expected redo stack to be cleared by Record()")
+	}
+
+	e, _ = h.Undo()
+	if e.Description != "third" {
+		t.Errorf("Undo() = %q, want third", e.Description)
+	}
+	e, _ = h.Undo()
+	if e.Description != "first" {
+		t.Errorf("Undo() = %q, want first", e.Description)
+	}
+	if h.CanUndo() {
+		t.Error("Expected nothing left to undo")
+	}
+}
+
+func TestHistory_Limit(t *testing.T) {
+	h := New(2)
+	h.Record(Entry{Description: "a"})
+	h.Record(Entry{Description: "b"})
+	h.Record(Entry{Description: "c"})
+
+	var got []string
+	for {
+		e, ok := h.Undo()
+		if !ok {
+			break
+		}
+		got = append(got, e.Description)
+	}
+
+	if len(got) != 2 || got[0] != "c" || got[1] != "b" {
+		t.Errorf("Expected [c b], got %v", got)
+	}
+}
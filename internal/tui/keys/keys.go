@@ -0,0 +1,342 @@
+// Package keys defines the actions of the TUI models that keys can be bound to,
+// their default keys and presets, and builds the effective key bindings from
+// the user's configuration.
+package keys
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jhoffmann/bookmark-manager/internal/config"
+)
+
+// Action is something keys can be bound to in one of the TUI models
+type Action struct {
+	Name string   // Name in the config file
+	Help string   // Description in the help view
+	Keys []string // Default keys
+	// Contextual actions only apply in a situation that is checked before any
+	// other action, such as an error being shown, so they may share keys with
+	// other actions
+	Contextual bool
+}
+
+// Actions of the list, the delete confirmation and the text inputs (the edit
+// dialog and the filter), by scope
+var (
+	ListActions = []Action{
+		{Name: "up", Help: "up", Keys: []string{"up", "k"}},
+		{Name: "down", Help: "down", Keys: []string{"down", "j"}},
+		{Name: "page_up", Help: "prev page", Keys: []string{"pgup", "left", "h", "b"}},
+		{Name: "page_down", Help: "next page", Keys: []string{"pgdown", "right", "l", "f"}},
+		{Name: "home", Help: "go to start", Keys: []string{"home", "g"}},
+		{Name: "end", Help: "go to end", Keys: []string{"end", "G"}},
+		{Name: "next_tab", Help: "next category", Keys: []string{"tab"}},
+		{Name: "prev_tab", Help: "prev category", Keys: []string{"shift+tab"}},
+		// The nth key of goto_tab goes to the nth tab
+		{Name: "goto_tab", Help: "go to category", Keys: []string{"1", "2", "3", "4", "5", "6", "7", "8", "9"}},
+		{Name: "filter", Help: "filter bookmarks", Keys: []string{"/"}},
+		{Name: "clear_filter", Help: "clear filter", Keys: []string{"ctrl+u"}},
+		{Name: "open", Help: "open folder", Keys: []string{"enter"}},
+		{Name: "edit", Help: "edit category", Keys: []string{"e"}},
+		{Name: "alias", Help: "edit alias", Keys: []string{"a"}},
+		{Name: "delete", Help: "delete bookmark", Keys: []string{"x", "d"}},
+		{Name: "sort", Help: "toggle frecency sort", Keys: []string{"s"}},
+		{Name: "restore", Help: "restore from trash", Keys: []string{"r"}},
+		{Name: "undo", Help: "undo", Keys: []string{"u"}},
+		{Name: "redo", Help: "redo", Keys: []string{"ctrl+r"}},
+		{Name: "mark", Help: "toggle selection", Keys: []string{"space"}},
+		{Name: "select_all", Help: "select all shown", Keys: []string{"ctrl+a"}},
+		{Name: "export", Help: "export to file", Keys: []string{"E"}},
+		{Name: "rename_category", Help: "rename or merge category", Keys: []string{"R"}},
+		{Name: "remove_category", Help: "remove category", Keys: []string{"X"}},
+		{Name: "dismiss", Help: "dismiss error", Keys: []string{"esc"}, Contextual: true},
+		{Name: "help", Help: "toggle help", Keys: []string{"?"}},
+		{Name: "quit", Help: "quit", Keys: []string{"q", "esc", "ctrl+c"}},
+	}
+
+	ConfirmActions = []Action{
+		{Name: "up", Help: "up", Keys: []string{"up", "k"}},
+		{Name: "down", Help: "down", Keys: []string{"down", "j"}},
+		{Name: "select", Help: "choose", Keys: []string{"enter"}},
+		{Name: "yes", Help: "yes", Keys: []string{"y"}},
+		{Name: "no", Help: "no", Keys: []string{"n", "q", "esc"}},
+	}
+
+	EditActions = []Action{
+		{Name: "submit", Help: "save", Keys: []string{"enter"}},
+		{Name: "cancel", Help: "cancel", Keys: []string{"esc", "ctrl+c"}},
+	}
+)
+
+// DefaultPreset is the preset used when none is configured
+const DefaultPreset = "default"
+
+// presets replace the default keys of some actions, by scope and action
+var presets = map[string]map[string]map[string][]string{
+	DefaultPreset: {},
+	// vim: d and u are left to vim habits, esc never quits and the pages
+	// move with ctrl+f and ctrl+b
+	"vim": {
+		config.KeyScopeList: {
+			"page_up":   {"pgup", "ctrl+b"},
+			"page_down": {"pgdown", "ctrl+f", "ctrl+d"},
+			"next_tab":  {"tab", "]"},
+			"prev_tab":  {"shift+tab", "["},
+			"delete":    {"x"},
+			"quit":      {"q", "ctrl+c"},
+		},
+		config.KeyScopeConfirm: {
+			"no": {"n", "q", "esc"},
+		},
+	},
+	// emacs: ctrl and alt movement keys, ctrl+g cancels
+	"emacs": {
+		config.KeyScopeList: {
+			"up":        {"up", "ctrl+p"},
+			"down":      {"down", "ctrl+n"},
+			"page_up":   {"pgup", "alt+v"},
+			"page_down": {"pgdown", "ctrl+v"},
+			"home":      {"home", "alt+<"},
+			"end":       {"end", "alt+>"},
+			"undo":      {"u", "ctrl+_"},
+			"dismiss":   {"esc", "ctrl+g"},
+			"quit":      {"q", "ctrl+c"},
+		},
+		config.KeyScopeConfirm: {
+			"up":   {"up", "ctrl+p"},
+			"down": {"down", "ctrl+n"},
+			"no":   {"n", "q", "esc", "ctrl+g"},
+		},
+		config.KeyScopeEdit: {
+			"cancel": {"esc", "ctrl+c", "ctrl+g"},
+		},
+	},
+}
+
+// Presets returns the names of the presets, sorted
+func Presets() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ScopeActions returns the actions of a scope, nil for unknown scopes
+func ScopeActions(scope string) []Action {
+	switch scope {
+	case config.KeyScopeList:
+		return ListActions
+	case config.KeyScopeConfirm:
+		return ConfirmActions
+	case config.KeyScopeEdit:
+		return EditActions
+	}
+	return nil
+}
+
+// Map holds the effective bindings of one scope by action name
+type Map map[string]key.Binding
+
+// Get returns the binding of an action, a disabled binding for unknown actions
+func (m Map) Get(action string) key.Binding {
+	if b, ok := m[action]; ok {
+		return b
+	}
+	return key.NewBinding(key.WithDisabled())
+}
+
+// Bindings holds the effective bindings of every scope
+type Bindings struct {
+	List    Map
+	Confirm Map
+	Edit    Map
+}
+
+// Default returns the bindings of the default preset
+func Default() Bindings {
+	bindings, _ := Load(config.KeyBindings{})
+	return bindings
+}
+
+// Load builds the effective bindings: the default keys, replaced per action
+// by the preset and then by the configured keys. Unknown presets, actions and
+// key names and keys bound to several actions of a scope are reported
+// together; the bindings returned are usable either way.
+func Load(cfg config.KeyBindings) (Bindings, error) {
+	var errs []error
+
+	presetName := cfg.Preset
+	if presetName == "" {
+		presetName = DefaultPreset
+	}
+	preset, ok := presets[presetName]
+	if !ok {
+		errs = append(errs, fmt.Errorf("%spreset: unknown preset %q (expected one of %s)",
+			config.KeysPrefix, presetName, strings.Join(Presets(), ", ")))
+	}
+
+	maps := make(map[string]Map, len(config.KeyScopes))
+	for _, scope := range config.KeyScopes {
+		overrides, _ := cfg.Scope(scope)
+		m, err := build(scope, preset[scope], overrides)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		maps[scope] = m
+	}
+
+	return Bindings{
+		List:    maps[config.KeyScopeList],
+		Confirm: maps[config.KeyScopeConfirm],
+		Edit:    maps[config.KeyScopeEdit],
+	}, errors.Join(errs...)
+}
+
+// build creates the bindings of a scope, applying the preset and the
+// configured keys over the defaults
+func build(scope string, preset, overrides map[string][]string) (Map, error) {
+	var errs []error
+	actions := ScopeActions(scope)
+
+	// Configured actions must exist
+	names := make([]string, len(actions))
+	for i, a := range actions {
+		names[i] = a.Name
+	}
+	var unknown []string
+	for name := range overrides {
+		if !slices.Contains(names, name) {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		errs = append(errs, fmt.Errorf("%s%s.%s: unknown action (expected one of %s)",
+			config.KeysPrefix, scope, name, strings.Join(names, ", ")))
+	}
+
+	m := make(Map, len(actions))
+	boundTo := make(map[string][]string) // Non-contextual actions by key
+	for _, a := range actions {
+		keys := a.Keys
+		if preset[a.Name] != nil {
+			keys = preset[a.Name]
+		}
+		if overrides[a.Name] != nil {
+			keys = overrides[a.Name]
+		}
+
+		normalized := make([]string, 0, len(keys))
+		for _, k := range keys {
+			name, err := Normalize(k)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s%s.%s: %w", config.KeysPrefix, scope, a.Name, err))
+				continue
+			}
+			if slices.Contains(normalized, name) {
+				continue
+			}
+			normalized = append(normalized, name)
+			if !a.Contextual {
+				boundTo[name] = append(boundTo[name], a.Name)
+			}
+		}
+
+		m[a.Name] = NewBinding(normalized, a.Help)
+	}
+
+	// A key may only trigger one action
+	var conflicts []string
+	for k, bound := range boundTo {
+		if len(bound) > 1 {
+			conflicts = append(conflicts, fmt.Sprintf("%q is bound to %s", Display(k), strings.Join(bound, " and ")))
+		}
+	}
+	sort.Strings(conflicts)
+	for _, c := range conflicts {
+		errs = append(errs, fmt.Errorf("%s%s: conflicting bindings: %s", config.KeysPrefix, scope, c))
+	}
+
+	return m, errors.Join(errs...)
+}
+
+// NewBinding creates a binding whose help shows its keys, or a disabled one
+// when there are no keys
+func NewBinding(keys []string, help string) key.Binding {
+	if len(keys) == 0 {
+		return key.NewBinding(key.WithDisabled(), key.WithHelp("", help))
+	}
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(Help(keys), help))
+}
+
+// Help formats keys for the help view, as in "x/d". Runs of three or more
+// consecutive digits are shortened, as in "1-9".
+func Help(keys []string) string {
+	var shown []string
+	for i := 0; i < len(keys); i++ {
+		j := i
+		for j+1 < len(keys) && isDigit(keys[j]) && isDigit(keys[j+1]) && keys[j+1][0] == keys[j][0]+1 {
+			j++
+		}
+		if j-i >= 2 {
+			shown = append(shown, keys[i]+"-"+keys[j])
+			i = j
+			continue
+		}
+		shown = append(shown, Display(keys[i]))
+	}
+	return strings.Join(shown, "/")
+}
+
+// isDigit reports whether a key is a single digit
+func isDigit(k string) bool {
+	return len(k) == 1 && k[0] >= '0' && k[0] <= '9'
+}
+
+// Display returns the name a key is shown and configured with
+func Display(k string) string {
+	if k == " " {
+		return "space"
+	}
+	return k
+}
+
+// namedKeys are the key names bubbletea reports, such as "enter" or "ctrl+a"
+var namedKeys = func() map[string]bool {
+	names := make(map[string]bool)
+	// Key types are small integers, negative for special keys; the ones
+	// without a name are not keys
+	for t := tea.KeyType(-128); t < 128; t++ {
+		if name := t.String(); name != "" && name != "runes" {
+			names[name] = true
+		}
+	}
+	return names
+}()
+
+// Normalize checks a configured key name and returns it as bubbletea reports
+// it: a single character, a named key such as "enter", "ctrl+a" or "f5", or
+// either one prefixed with "alt+". "space" is accepted for the space bar.
+func Normalize(k string) (string, error) {
+	if k == "space" {
+		return " ", nil
+	}
+
+	base := strings.TrimPrefix(k, "alt+")
+	if base == "space" {
+		return "alt+ ", nil
+	}
+	if utf8.RuneCountInString(base) == 1 || namedKeys[base] {
+		return k, nil
+	}
+	return "", fmt.Errorf("unknown key %q (expected a character, a key name such as enter, tab or ctrl+a, or space)", k)
+}
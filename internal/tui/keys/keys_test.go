@@ -0,0 +1,277 @@
+package keys
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jhoffmann/bookmark-manager/internal/config"
+)
+
+func TestLoad_Default(t *testing.T) {
+	bindings, err := Load(config.KeyBindings{})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	for _, scope := range config.KeyScopes {
+		m := map[string]Map{
+			config.KeyScopeList:    bindings.List,
+			config.KeyScopeConfirm: bindings.Confirm,
+			config.KeyScopeEdit:    bindings.Edit,
+		}[scope]
+		for _, a := range ScopeActions(scope) {
+			b := m.Get(a.Name)
+			if !b.Enabled() {
+				t.Errorf("%s.%s is disabled", scope, a.Name)
+			}
+			if b.Help().Desc != a.Help {
+				t.Errorf("%s.%s help = %q, want %q", scope, a.Name, b.Help().Desc, a.Help)
+			}
+		}
+	}
+
+	if got := bindings.List.Get("delete").Keys(); !slices.Equal(got, []string{"x", "d"}) {
+		t.Errorf("delete keys = %v, want [x d]", got)
+	}
+	if got := bindings.List.Get("mark").Keys(); !slices.Equal(got, []string{" "}) {
+		t.Errorf("mark keys = %q, want [\" \"]", got)
+	}
+	if got := bindings.List.Get("mark").Help().Key; got != "space" {
+		t.Errorf("mark help = %q, want space", got)
+	}
+	if bindings.List.Get("unknown").Enabled() {
+		t.Error("Expected unknown actions to be disabled")
+	}
+}
+
+func TestLoad_Presets(t *testing.T) {
+	tests := []struct {
+		preset string
+		scope  string
+		action string
+		want   []string
+	}{
+		{"vim", config.KeyScopeList, "delete", []string{"x"}},
+		{"vim", config.KeyScopeList, "quit", []string{"q", "ctrl+c"}},
+		{"vim", config.KeyScopeList, "up", []string{"up", "k"}},
+		{"emacs", config.KeyScopeList, "down", []string{"down", "ctrl+n"}},
+		{"emacs", config.KeyScopeEdit, "cancel", []string{"esc", "ctrl+c", "ctrl+g"}},
+		{"default", config.KeyScopeList, "delete", []string{"x", "d"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.preset+"/"+tt.action, func(t *testing.T) {
+			bindings, err := Load(config.KeyBindings{Preset: tt.preset})
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			m := map[string]Map{
+				config.KeyScopeList:    bindings.List,
+				config.KeyScopeConfirm: bindings.Confirm,
+				config.KeyScopeEdit:    bindings.Edit,
+			}[tt.scope]
+			if got := m.Get(tt.action).Keys(); !slices.Equal(got, tt.want) {
+				t.Errorf("%s keys = %v, want %v", tt.action, got, tt.want)
+			}
+		})
+	}
+
+	// Every preset is free of conflicts
+	for _, name := range Presets() {
+		if _, err := Load(config.KeyBindings{Preset: name}); err != nil {
+			t.Errorf("preset %s: %v", name, err)
+		}
+	}
+}
+
+func TestLoad_Overrides(t *testing.T) {
+	bindings, err := Load(config.KeyBindings{
+		Preset: "vim",
+		List:   map[string][]string{"delete": {"x", "D"}, "mark": {"space", "m"}, "undo": {}},
+		Edit:   map[string][]string{"submit": {"enter", "ctrl+s"}},
+	})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if got := bindings.List.Get("delete").Help().Key; got != "x/D" {
+		t.Errorf("delete help = %q, want x/D", got)
+	}
+	if got := bindings.List.Get("mark").Keys(); !slices.Equal(got, []string{" ", "m"}) {
+		t.Errorf("mark keys = %q, want [\" \" m]", got)
+	}
+	if got := bindings.Edit.Get("submit").Keys(); !slices.Equal(got, []string{"enter", "ctrl+s"}) {
+		t.Errorf("submit keys = %v, want [enter ctrl+s]", got)
+	}
+	if bindings.List.Get("undo").Enabled() {
+		t.Error("Expected an empty list to unbind undo")
+	}
+	// The preset still applies to the other actions
+	if got := bindings.List.Get("page_down").Keys(); !slices.Contains(got, "ctrl+f") {
+		t.Errorf("page_down keys = %v, want ctrl+f from the vim preset", got)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.KeyBindings
+		want []string
+	}{
+		{
+			name: "unknown preset",
+			cfg:  config.KeyBindings{Preset: "nano"},
+			want: []string{`keys.preset: unknown preset "nano"`},
+		},
+		{
+			name: "unknown action",
+			cfg:  config.KeyBindings{Confirm: map[string][]string{"maybe": {"m"}}},
+			want: []string{"keys.confirm.maybe: unknown action"},
+		},
+		{
+			name: "unknown key",
+			cfg:  config.KeyBindings{List: map[string][]string{"sort": {"ctrl+zz"}}},
+			want: []string{`keys.list.sort: unknown key "ctrl+zz"`},
+		},
+		{
+			name: "conflict",
+			cfg:  config.KeyBindings{List: map[string][]string{"sort": {"x"}}},
+			want: []string{`keys.list: conflicting bindings: "x" is bound to delete and sort`},
+		},
+		{
+			name: "conflict with space",
+			cfg:  config.KeyBindings{List: map[string][]string{"open": {"space"}}},
+			want: []string{`"space" is bound to open and mark`},
+		},
+		{
+			name: "several errors",
+			cfg: config.KeyBindings{
+				Preset: "nano",
+				Edit:   map[string][]string{"submit": {"esc"}},
+			},
+			want: []string{"unknown preset", `keys.edit: conflicting bindings: "esc" is bound to submit and cancel`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bindings, err := Load(tt.cfg)
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error = %q, want it to contain %q", err, want)
+				}
+			}
+			// The bindings are usable either way
+			if !bindings.List.Get("quit").Enabled() {
+				t.Error("Expected the bindings to be usable despite the error")
+			}
+		})
+	}
+}
+
+func TestLoad_ContextualActionsMayShareKeys(t *testing.T) {
+	// dismiss shares esc with quit by default
+	bindings, err := Load(config.KeyBindings{})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !slices.Contains(bindings.List.Get("dismiss").Keys(), "esc") ||
+		!slices.Contains(bindings.List.Get("quit").Keys(), "esc") {
+		t.Error("Expected esc to be bound to both dismiss and quit")
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		key     string
+		want    string
+		wantErr bool
+	}{
+		{"x", "x", false},
+		{"D", "D", false},
+		{"?", "?", false},
+		{"space", " ", false},
+		{"alt+space", "alt+ ", false},
+		{"enter", "enter", false},
+		{"shift+tab", "shift+tab", false},
+		{"ctrl+r", "ctrl+r", false},
+		{"pgdown", "pgdown", false},
+		{"f5", "f5", false},
+		{"alt+v", "alt+v", false},
+		{"alt+<", "alt+<", false},
+		{"é", "é", false},
+		{"", "", true},
+		{"xx", "", true},
+		{"ctrl+zz", "", true},
+		{"return", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, err := Normalize(tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Normalize(%q) error = %v, wantErr %v", tt.key, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalize_MatchesKeyMessages(t *testing.T) {
+	// Configured names must equal what bubbletea reports for the key
+	tests := []struct {
+		key string
+		msg tea.KeyMsg
+	}{
+		{"space", tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}},
+		{"enter", tea.KeyMsg{Type: tea.KeyEnter}},
+		{"ctrl+f", tea.KeyMsg{Type: tea.KeyCtrlF}},
+		{"alt+v", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'v'}, Alt: true}},
+		{"G", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}}},
+	}
+
+	for _, tt := range tests {
+		got, err := Normalize(tt.key)
+		if err != nil {
+			t.Fatalf("Normalize(%q) error = %v", tt.key, err)
+		}
+		if got != tt.msg.String() {
+			t.Errorf("Normalize(%q) = %q, but the key is reported as %q", tt.key, got, tt.msg.String())
+		}
+	}
+}
+
+func TestNewBinding_NoKeys(t *testing.T) {
+	b := NewBinding(nil, "undo")
+	if b.Enabled() {
+		t.Error("Expected a binding without keys to be disabled")
+	}
+	if got := Help([]string{"x", " ", "ctrl+r"}); got != "x/space/ctrl+r" {
+		t.Errorf("Help() = %q, want x/space/ctrl+r", got)
+	}
+}
+
+func TestHelp_DigitRuns(t *testing.T) {
+	tests := []struct {
+		keys []string
+		want string
+	}{
+		{[]string{"1", "2", "3", "4", "5", "6", "7", "8", "9"}, "1-9"},
+		{[]string{"1", "2"}, "1/2"},
+		{[]string{"1", "2", "3", "5", "x"}, "1-3/5/x"},
+		{[]string{"alt+1", "alt+2", "alt+3"}, "alt+1/alt+2/alt+3"},
+	}
+
+	for _, tt := range tests {
+		if got := Help(tt.keys); got != tt.want {
+			t.Errorf("Help(%q) = %q, want %q", tt.keys, got, tt.want)
+		}
+	}
+}
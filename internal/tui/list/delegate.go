@@ -0,0 +1,96 @@
+package list
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jhoffmann/bookmark-manager/internal/models"
+	"github.com/jhoffmann/bookmark-manager/internal/tui/styles"
+)
+
+// itemDelegate renders bookmark items like the default delegate, highlighting
+// the characters matched by the filter
+type itemDelegate struct {
+	list.DefaultDelegate
+}
+
+func newItemDelegate() itemDelegate {
+	d := list.NewDefaultDelegate()
+	d.Styles = styles.ItemStyles()
+	return itemDelegate{DefaultDelegate: d}
+}
+
+// Render implements list.ItemDelegate
+func (d itemDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	i, ok := item.(bookmarkItem)
+	if !ok || (len(i.folderMatches) == 0 && len(i.tagMatches) == 0 && len(i.aliasMatches) == 0) || m.Width() <= 0 {
+		d.DefaultDelegate.Render(w, m, index, item)
+		return
+	}
+
+	s := &d.Styles
+	titleStyle, descStyle := s.NormalTitle, s.NormalDesc
+	if index == m.Index() {
+		titleStyle, descStyle = s.SelectedTitle, s.SelectedDesc
+	}
+
+	// Prevent text from exceeding list width
+	textWidth := m.Width() - s.NormalTitle.GetPaddingLeft() - s.NormalTitle.GetPaddingRight()
+
+	// Alias matches are relative to the alias, which follows the mark prefix
+	// and the alias prefix; folder matches are relative to the folder, which
+	// follows the alias
+	prefixLen := len([]rune(i.titlePrefix()))
+	aliasOffset := prefixLen + len([]rune(models.AliasPrefix))
+	folderOffset := prefixLen + len([]rune(i.titleAlias()))
+	titleMatches := make([]int, 0, len(i.aliasMatches)+len(i.folderMatches))
+	for _, pos := range i.aliasMatches {
+		titleMatches = append(titleMatches, pos+aliasOffset)
+	}
+	for _, pos := range i.folderMatches {
+		titleMatches = append(titleMatches, pos+folderOffset)
+	}
+
+	title := highlight(truncate(i.Title(), textWidth), titleMatches, titleStyle, s.FilterMatch)
+	title = titleStyle.Render(title)
+	if !d.ShowDescription {
+		fmt.Fprint(w, title) //nolint: errcheck
+		return
+	}
+
+	// Tag matches are relative to the tag list, which follows the prefix
+	prefix, tags := i.descriptionParts()
+	offset := len([]rune(prefix))
+	tagMatches := make([]int, len(i.tagMatches))
+	for n, pos := range i.tagMatches {
+		tagMatches[n] = pos + offset
+	}
+
+	desc := highlight(truncate(prefix+tags, textWidth), tagMatches, descStyle, s.FilterMatch)
+	desc = descStyle.Render(desc)
+
+	fmt.Fprintf(w, "%s\n%s", title, desc) //nolint: errcheck
+}
+
+// highlight styles the runes at the given positions with the match style
+// layered over the base style
+func highlight(text string, positions []int, base, match lipgloss.Style) string {
+	if len(positions) == 0 {
+		return text
+	}
+	unmatched := base.Inline(true)
+	matched := match.Inherit(unmatched)
+	return lipgloss.StyleRunes(text, positions, matched, unmatched)
+}
+
+// truncate shortens text to at most width runes, marking the cut with an
+// ellipsis
+func truncate(text string, width int) string {
+	runes := []rune(text)
+	if width <= 0 || len(runes) <= width {
+		return text
+	}
+	return string(runes[:width-1]) + "…"
+}
@@ -0,0 +1,68 @@
+package list
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// minInlineHeight is the fewest lines an inline list is given, so that a small
+// percentage of a short terminal still leaves room for the title and a bookmark
+const minInlineHeight = 10
+
+// Height limits how many terminal lines the TUI occupies, either as a number
+// of lines or as a percentage of the terminal height. The zero value means
+// fullscreen.
+type Height struct {
+	Value   int
+	Percent bool
+}
+
+// ParseHeight parses a height such as "20" (lines) or "40%"; an empty string
+// or "100%" is fullscreen
+func ParseHeight(s string) (Height, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Height{}, nil
+	}
+
+	text, percent := strings.CutSuffix(s, "%")
+	value, err := strconv.Atoi(text)
+	if err != nil || value <= 0 || (percent && value > 100) {
+		return Height{}, fmt.Errorf("invalid height %q: expected a number of lines or a percentage such as 40%%", s)
+	}
+
+	if percent && value == 100 {
+		return Height{}, nil
+	}
+	return Height{Value: value, Percent: percent}, nil
+}
+
+// Fullscreen reports whether the height leaves the whole terminal to the TUI
+func (h Height) Fullscreen() bool {
+	return h.Value == 0
+}
+
+// Lines returns the number of lines to use in a terminal of the given height
+func (h Height) Lines(terminal int) int {
+	if h.Fullscreen() {
+		return terminal
+	}
+
+	lines := h.Value
+	if h.Percent {
+		lines = terminal * h.Value / 100
+	}
+	return min(max(lines, minInlineHeight), terminal)
+}
+
+// String formats the height as accepted by ParseHeight
+func (h Height) String() string {
+	switch {
+	case h.Fullscreen():
+		return "100%"
+	case h.Percent:
+		return strconv.Itoa(h.Value) + "%"
+	}
+	return strconv.Itoa(h.Value)
+}
@@ -0,0 +1,53 @@
+package list
+
+import "testing"
+
+func TestParseHeight(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Height
+		wantErr bool
+	}{
+		{in: "", want: Height{}},
+		{in: "100%", want: Height{}},
+		{in: "40%", want: Height{Value: 40, Percent: true}},
+		{in: " 20 ", want: Height{Value: 20}},
+		{in: "0", wantErr: true},
+		{in: "-5", wantErr: true},
+		{in: "150%", wantErr: true},
+		{in: "half", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseHeight(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseHeight(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseHeight(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeightLines(t *testing.T) {
+	tests := []struct {
+		height   Height
+		terminal int
+		want     int
+	}{
+		{Height{}, 50, 50},
+		{Height{Value: 40, Percent: true}, 50, 20},
+		{Height{Value: 20}, 50, 20},
+		{Height{Value: 80}, 50, 50},                // never taller than the terminal
+		{Height{Value: 10, Percent: true}, 30, 10}, // at least minInlineHeight
+		{Height{Value: 3}, 6, 6},
+	}
+
+	for _, tt := range tests {
+		if got := tt.height.Lines(tt.terminal); got != tt.want {
+			t.Errorf("%v.Lines(%d) = %d, want %d", tt.height, tt.terminal, got, tt.want)
+		}
+	}
+}
@@ -2,7 +2,9 @@
 package list
 
 import (
-	"sort"
+	"errors"
+	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -11,148 +13,255 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jhoffmann/bookmark-manager/internal/models"
+	"github.com/jhoffmann/bookmark-manager/internal/query"
 	svc "github.com/jhoffmann/bookmark-manager/internal/service"
 	"github.com/jhoffmann/bookmark-manager/internal/tui/confirm"
 	"github.com/jhoffmann/bookmark-manager/internal/tui/edit"
+	"github.com/jhoffmann/bookmark-manager/internal/tui/history"
+	"github.com/jhoffmann/bookmark-manager/internal/tui/keys"
+	"github.com/jhoffmann/bookmark-manager/internal/tui/status"
 	"github.com/jhoffmann/bookmark-manager/internal/tui/styles"
 )
 
 var docStyle = lipgloss.NewStyle().Margin(1, 2)
 
+// Names of the tabs that are not categories
+const (
+	allTabName   = "All"
+	trashTabName = "Trash"
+)
+
+// confirmAction is what the confirm dialog asks about
+type confirmAction int
+
+const (
+	confirmDelete         confirmAction = iota // Moving bookmarks to the trash
+	confirmPurge                               // Deleting bookmarks permanently
+	confirmRemoveCategory                      // Removing a category from its bookmarks
+)
+
 // Model represents the main TUI state for the bookmark list
 type Model struct {
 	list            list.Model
 	categories      []string
-	activeCategory  string
+	counts          map[string]int // Bookmarks in each category
+	activeTab       tabKind
+	activeCategory  string // Category of the active tab, if it is a category tab
+	tabOffset       int    // First tab shown when they don't all fit
 	filter          textinput.Model
 	filterFocused   bool
 	bookmarks       []*models.Bookmark
 	allBookmarks    []*models.Bookmark
+	trashed         []*models.Bookmark
+	folderStatus    map[uint]svc.FolderStatus // Folders checked on disk by bookmark ID
+	checking        map[uint]bool             // Bookmarks whose folder is being checked
 	bookmarkService *svc.Bookmarks
 	folderService   *svc.Folders
 	keys            keyMap
 	confirmDialog   confirm.Model
 	editDialog      edit.Model
+	history         history.History
+	status          status.Model
 	showingDialog   bool
+	confirming      confirmAction // What the confirm dialog asks about
+	category        string        // Category the confirm dialog asks about
 	showingEdit     bool
 	windowSize      tea.WindowSizeMsg
 	err             error
 	cwdFile         string
-	savedCursor     int // Store cursor position when dialogs open
+	printMode       bool               // Whether enter selects bookmarks for the caller instead of opening them
+	marked          map[uint]bool      // Selected bookmarks, which actions apply to instead of the current one
+	selection       []*models.Bookmark // Bookmarks chosen in print mode
+	warning         error              // Failure to report after quitting
+	sortOrder       svc.SortOrder
+	query           *query.Query // Restricts the bookmarks shown, nil for all
+	height          Height       // Lines of the terminal to draw in, zero for fullscreen
+	quitting        bool         // Whether the program is exiting, so inline output is cleared
+	savedCursor     int          // Store cursor position when dialogs open
 }
 
 // bookmarkItem implements list.Item for use with bubbles/list
 type bookmarkItem struct {
 	bookmark *models.Bookmark
+	status   svc.FolderStatus
+	marked   bool
+	// Rune positions matched by the filter in the folder, the tag list and
+	// the alias
+	folderMatches []int
+	tagMatches    []int
+	aliasMatches  []int
 }
 
 func (i bookmarkItem) FilterValue() string {
-	return i.bookmark.Folder + " " + string(i.bookmark.Category)
+	return i.bookmark.Folder + " " + strings.Join(i.bookmark.TagNames(), " ") + " " + i.bookmark.Alias
 }
 
 func (i bookmarkItem) Title() string {
-	return i.bookmark.Folder
+	return i.titlePrefix() + i.titleAlias() + i.bookmark.Folder
+}
+
+// titlePrefix marks selected bookmarks
+func (i bookmarkItem) titlePrefix() string {
+	if i.marked {
+		return "● "
+	}
+	return ""
+}
+
+// titleAlias shows the alias ahead of the folder
+func (i bookmarkItem) titleAlias() string {
+	if i.bookmark.Alias == "" {
+		return ""
+	}
+	return models.AliasPrefix + i.bookmark.Alias + "  "
 }
 
 func (i bookmarkItem) Description() string {
-	return string(i.bookmark.Category)
+	prefix, tags := i.descriptionParts()
+	return prefix + tags
+}
+
+// descriptionParts splits the description into the state prefix (deletion
+// date or folder problem) and the tag list, which is what the filter matches
+func (i bookmarkItem) descriptionParts() (prefix, tags string) {
+	tags = strings.Join(i.bookmark.TagNames(), svc.TagSeparator)
+
+	var state string
+	if i.bookmark.DeletedAt.Valid {
+		state = "deleted " + i.bookmark.DeletedAt.Time.Format("2006-01-02 15:04")
+	} else if i.status != "" && i.status != svc.FolderOK {
+		state = "⚠ " + string(i.status)
+	}
+
+	switch {
+	case state == "":
+		return "", tags
+	case tags == "":
+		return state, ""
+	default:
+		return state + " · ", tags
+	}
 }
 
 // keyMap defines key bindings for the list interface
 type keyMap struct {
-	NextTab     key.Binding
-	PrevTab     key.Binding
-	Delete      key.Binding
-	Edit        key.Binding
-	Filter      key.Binding
-	Quit        key.Binding
-	ClearFilter key.Binding
-	Enter       key.Binding
+	NextTab      key.Binding
+	PrevTab      key.Binding
+	GotoTab      key.Binding // The nth key goes to the nth tab
+	Delete       key.Binding
+	Edit         key.Binding
+	Alias        key.Binding
+	Filter       key.Binding
+	Quit         key.Binding
+	ClearFilter  key.Binding
+	Enter        key.Binding
+	Sort         key.Binding
+	Restore      key.Binding
+	Undo         key.Binding
+	Redo         key.Binding
+	Dismiss      key.Binding
+	Mark         key.Binding
+	SelectAll    key.Binding
+	Export       key.Binding
+	RenameCat    key.Binding
+	RemoveCat    key.Binding
+	ApplyFilter  key.Binding // Leaves the filter input keeping the filter
+	CancelFilter key.Binding // Leaves the filter input
+}
+
+// newKeyMap picks the list's bindings from the effective ones. The filter
+// input shares the bindings of the edit dialog.
+func newKeyMap(bindings keys.Bindings) keyMap {
+	return keyMap{
+		NextTab:      bindings.List.Get("next_tab"),
+		PrevTab:      bindings.List.Get("prev_tab"),
+		GotoTab:      bindings.List.Get("goto_tab"),
+		Delete:       bindings.List.Get("delete"),
+		Edit:         bindings.List.Get("edit"),
+		Alias:        bindings.List.Get("alias"),
+		Filter:       bindings.List.Get("filter"),
+		Quit:         bindings.List.Get("quit"),
+		ClearFilter:  bindings.List.Get("clear_filter"),
+		Enter:        bindings.List.Get("open"),
+		Sort:         bindings.List.Get("sort"),
+		Restore:      bindings.List.Get("restore"),
+		Undo:         bindings.List.Get("undo"),
+		Redo:         bindings.List.Get("redo"),
+		Dismiss:      bindings.List.Get("dismiss"),
+		Mark:         bindings.List.Get("mark"),
+		SelectAll:    bindings.List.Get("select_all"),
+		Export:       bindings.List.Get("export"),
+		RenameCat:    bindings.List.Get("rename_category"),
+		RemoveCat:    bindings.List.Get("remove_category"),
+		ApplyFilter:  bindings.Edit.Get("submit"),
+		CancelFilter: bindings.Edit.Get("cancel"),
+	}
 }
 
 // DefaultKeyMap returns the default key bindings
 func DefaultKeyMap() keyMap {
-	return keyMap{
-		NextTab: key.NewBinding(
-			key.WithKeys("tab"),
-			key.WithHelp("tab", "next category"),
-		),
-		PrevTab: key.NewBinding(
-			key.WithKeys("shift+tab"),
-			key.WithHelp("shift+tab", "prev category"),
-		),
-		Delete: key.NewBinding(
-			key.WithKeys("x", "d"),
-			key.WithHelp("x/d", "delete bookmark"),
-		),
-		Edit: key.NewBinding(
-			key.WithKeys("e"),
-			key.WithHelp("e", "edit category"),
-		),
-		Filter: key.NewBinding(
-			key.WithKeys("/"),
-			key.WithHelp("/", "filter bookmarks"),
-		),
-		Quit: key.NewBinding(
-			key.WithKeys("q", "esc", "ctrl+c"),
-			key.WithHelp("q", "quit"),
-		),
-		ClearFilter: key.NewBinding(
-			key.WithKeys("ctrl+u"),
-			key.WithHelp("ctrl+u", "clear filter"),
-		),
-		Enter: key.NewBinding(
-			key.WithKeys("enter"),
-			key.WithHelp("enter", "open folder"),
-		),
-	}
-}
-
-// New creates a new list model
+	return newKeyMap(keys.Default())
+}
+
+// listKeyMap sets the navigation bindings of the bubbles list from the
+// effective ones
+func listKeyMap(bindings keys.Map) list.KeyMap {
+	km := list.DefaultKeyMap()
+	km.CursorUp = bindings.Get("up")
+	km.CursorDown = bindings.Get("down")
+	km.PrevPage = bindings.Get("page_up")
+	km.NextPage = bindings.Get("page_down")
+	km.GoToStart = bindings.Get("home")
+	km.GoToEnd = bindings.Get("end")
+	km.Quit = bindings.Get("quit")
+
+	helpKeys := bindings.Get("help").Keys()
+	km.ShowFullHelp = keys.NewBinding(helpKeys, "more")
+	km.CloseFullHelp = keys.NewBinding(helpKeys, "close help")
+	return km
+}
+
+// New creates a new list model showing the tab of the initial category, or
+// all bookmarks when it is empty
 func New(service *svc.Bookmarks, initialCategory string) Model {
 	// Initialize text input for filtering
 	filterInput := textinput.New()
-	filterInput.Placeholder = "Type to filter bookmarks..."
+	filterInput.Placeholder = "Type to filter bookmarks (@ for aliases)..."
 	filterInput.CharLimit = 156
+	styles.StyleInput(&filterInput)
 
 	// Initialize list
 	items := []list.Item{}
-	delegate := list.NewDefaultDelegate()
+	delegate := newItemDelegate()
 
 	l := list.New(items, delegate, 0, 0)
-	l.Title = "All" // Start with "All" category
+	l.Title = "Bookmarks"
 	l.SetShowStatusBar(true)
 	l.SetFilteringEnabled(false) // We handle filtering ourselves
+	styles.StyleList(&l)
 
-	// Create the model first so we can reference it in the closure
-	keys := DefaultKeyMap()
-
-	// Set up additional help keys that show in full help view
-	l.AdditionalFullHelpKeys = func() []key.Binding {
-		return []key.Binding{
-			keys.NextTab,
-			keys.PrevTab,
-			keys.Filter,
-			keys.ClearFilter,
-			keys.Enter,
-			keys.Edit,
-			keys.Delete,
-			keys.Quit,
-		}
-	}
-
-	return Model{
+	m := Model{
 		list:            l,
-		categories:      []string{"All"},
-		activeCategory:  initialCategory,
+		activeTab:       allTab,
 		filter:          filterInput,
 		filterFocused:   false,
-		keys:            keys,
 		confirmDialog:   confirm.New(),
 		editDialog:      edit.New(),
+		history:         history.New(100),
+		status:          status.New(),
 		bookmarkService: service,
 		folderService:   svc.NewFolders(),
+		marked:          make(map[uint]bool),
+		folderStatus:    make(map[uint]svc.FolderStatus),
+		checking:        make(map[uint]bool),
+		sortOrder:       svc.SortByCategory,
+	}
+	if initialCategory != "" {
+		m.activeTab = categoryTab
+		m.activeCategory = initialCategory
 	}
+	m.SetKeys(keys.Default())
+	return m
 }
 
 // Init initializes the model (required by tea.Model interface)
@@ -164,98 +273,96 @@ func (m Model) Init() tea.Cmd {
 func (m *Model) LoadBookmarks() tea.Cmd {
 	return func() tea.Msg {
 		// Load all bookmarks
-		allBookmarks, err := m.bookmarkService.List(0, 0)
+		allBookmarks, err := m.bookmarkService.Find(m.query, m.sortOrder, 0, 0)
 		if err != nil {
-			return errMsg{err}
+			return errMsg{"Failed to load bookmarks", err}
 		}
 
-		// Extract unique categories
-		categorySet := make(map[string]bool)
-		categorySet["All"] = true
-
-		for _, b := range allBookmarks {
-			// Only add non-empty categories to the category set
-			if string(b.Category) != "" {
-				categorySet[string(b.Category)] = true
+		// Each tag gets its own category tab, shown with its number of
+		// bookmarks as counted by 'category list'
+		categoryCounts, err := m.bookmarkService.CategoryCounts(m.query)
+		if err != nil {
+			return errMsg{"Failed to count categories", err}
+		}
+		counts := make(map[string]int, len(categoryCounts))
+		categories := make([]string, 0, len(categoryCounts))
+		for _, c := range categoryCounts {
+			if c.Category != "" {
+				counts[string(c.Category)] = c.Count
+				categories = append(categories, string(c.Category))
 			}
 		}
 
-		categories := make([]string, 0, len(categorySet))
-		categories = append(categories, "All")
-		for cat := range categorySet {
-			if cat != "All" {
-				categories = append(categories, cat)
-			}
+		// Load the trash; its tab is only shown when there is something in it
+		trashed, err := m.bookmarkService.ListDeleted()
+		if err != nil {
+			return errMsg{"Failed to load the trash", err}
 		}
-		sort.Strings(categories[1:]) // Sort all except "All"
 
 		return bookmarksLoadedMsg{
 			bookmarks:  allBookmarks,
+			trashed:    trashed,
 			categories: categories,
+			counts:     counts,
 		}
 	}
 }
 
 // Update handles input events
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	var cmd tea.Cmd
-	var cmds []tea.Cmd
+	switch size := msg.(type) {
+	case tea.WindowSizeMsg:
+		// Inline, the list and its dialogs only get part of the terminal
+		size.Height = m.height.Lines(size.Height)
+		msg = size
+	case quitMsg:
+		// Render an empty view last so inline output is cleared on exit
+		m.quitting = true
+		m.warning = size.warning
+		return m, tea.Quit
+	}
 
-	// Handle confirmation dialog first
-	if m.showingDialog {
-		newConfirm, confirmCmd := m.confirmDialog.Update(msg)
-		m.confirmDialog = newConfirm
+	// Expire toasts even while a dialog is open
+	m.status, _ = m.status.Update(msg)
 
-		// Check if user made a choice
-		if m.confirmDialog.HasResult() {
-			m.showingDialog = false
-			result := m.confirmDialog.GetResult()
-			if result.Confirmed && result.Bookmark != nil {
-				return m, m.deleteBookmark(result.Bookmark)
-			}
-			// Dialog was cancelled - restore saved cursor position
-			m.list.Select(m.savedCursor)
-			return m, nil
-		}
+	m, cmd := m.update(msg)
 
-		return m, confirmCmd
-	}
+	// Notifications change height, so the list is resized after every update;
+	// the tab bar scrolls to keep the active tab in view
+	m.resize()
+	m.tabOffset = m.tabs().offset
+	return m, cmd
+}
 
-	// Handle edit dialog
-	if m.showingEdit {
-		newEdit, editCmd := m.editDialog.Update(msg)
-		m.editDialog = newEdit
+func (m Model) update(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	var cmds []tea.Cmd
 
-		// Check if user made a choice
-		if m.editDialog.HasResult() {
-			m.showingEdit = false
-			result := m.editDialog.GetResult()
-			if result.Submitted && result.Bookmark != nil {
-				return m, m.updateBookmarkCategory(result.Bookmark, result.NewCategory)
-			}
-			// Dialog was cancelled - restore saved cursor position
-			m.list.Select(m.savedCursor)
-			return m, nil
+	// While a dialog is open it takes the input. Other messages, like the
+	// results of commands and window sizes, reach it and the list behind it.
+	if m.showingDialog || m.showingEdit {
+		switch msg.(type) {
+		case tea.KeyMsg, tea.MouseMsg:
+			return m.updateDialog(msg)
 		}
-
-		return m, editCmd
+		m, cmd = m.updateDialog(msg)
+		cmds = append(cmds, cmd)
 	}
 
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.windowSize = msg // Store the current window size
-		h, v := docStyle.GetFrameSize()
-		m.list.SetSize(msg.Width-h, msg.Height-v-4) // Reserve space for filter
+		h, _ := docStyle.GetFrameSize()
 		m.filter.Width = msg.Width - h - 10
 
 	case tea.KeyMsg:
 		// Handle filter input when focused
 		if m.filterFocused {
-			switch msg.String() {
-			case "esc":
+			switch {
+			case key.Matches(msg, m.keys.CancelFilter):
 				m.filterFocused = false
 				m.filter.Blur()
-			case "enter":
+			case key.Matches(msg, m.keys.ApplyFilter):
 				m.filterFocused = false
 				m.filter.Blur()
 				return m, m.applyFilter()
@@ -270,7 +377,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Handle main interface key bindings
 		switch {
+		case m.status.HasError() && key.Matches(msg, m.keys.Dismiss):
+			m.status.Dismiss()
+			return m, nil
+
 		case key.Matches(msg, m.keys.Quit):
+			m.quitting = true
 			return m, tea.Quit
 
 		case key.Matches(msg, m.keys.Filter):
@@ -287,14 +399,26 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, m.keys.PrevTab):
 			return m, m.prevCategory()
 
+		case key.Matches(msg, m.keys.GotoTab):
+			if i := slices.Index(m.keys.GotoTab.Keys(), msg.String()); i >= 0 && i < len(m.tabList()) {
+				return m, m.selectTab(i)
+			}
+			return m, nil
+
 		case key.Matches(msg, m.keys.Delete):
-			if selectedItem, ok := m.list.SelectedItem().(bookmarkItem); ok {
+			if targets := m.targets(); len(targets) > 0 {
 				// Save current cursor position before opening dialog
 				m.savedCursor = m.list.Index()
-				m.confirmDialog.Show(
-					selectedItem.bookmark,
-					"", // No longer needed with new simple dialog
-				)
+				m.confirming = confirmDelete
+				message := ""
+				if m.inTrash() {
+					m.confirming = confirmPurge
+					message = "Permanently delete: " + targets[0].Folder + "?"
+					if len(targets) > 1 {
+						message = fmt.Sprintf("Permanently delete %d bookmarks?", len(targets))
+					}
+				}
+				m.confirmDialog.Show(targets, message)
 				m.showingDialog = true
 				// Immediately send the current window size to the confirm dialog
 				if m.windowSize.Width > 0 && m.windowSize.Height > 0 {
@@ -302,67 +426,281 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
+		case key.Matches(msg, m.keys.Undo):
+			if entry, ok := m.history.Undo(); ok {
+				return m, m.applyHistory(entry, true)
+			}
+			cmd = m.status.Info("Nothing to undo")
+			return m, cmd
+
+		case key.Matches(msg, m.keys.Redo):
+			if entry, ok := m.history.Redo(); ok {
+				return m, m.applyHistory(entry, false)
+			}
+			cmd = m.status.Info("Nothing to redo")
+			return m, cmd
+
+		case key.Matches(msg, m.keys.Restore):
+			if targets := m.targets(); len(targets) > 0 && m.inTrash() {
+				return m, m.restoreBookmarks(targets)
+			}
+
 		case key.Matches(msg, m.keys.Edit):
-			if selectedItem, ok := m.list.SelectedItem().(bookmarkItem); ok {
+			if targets := m.targets(); len(targets) > 0 && !m.inTrash() {
 				// Save current cursor position before opening dialog
 				m.savedCursor = m.list.Index()
-				m.editDialog.Show(selectedItem.bookmark)
+				m.editDialog.Show(targets, edit.FieldCategory)
 				m.showingEdit = true
 			}
 
+		case key.Matches(msg, m.keys.Alias):
+			// Aliases are unique, so only the current bookmark gets one
+			if selectedItem, ok := m.list.SelectedItem().(bookmarkItem); ok && !m.inTrash() {
+				// Save current cursor position before opening dialog
+				m.savedCursor = m.list.Index()
+				m.editDialog.Show([]*models.Bookmark{selectedItem.bookmark}, edit.FieldAlias)
+				m.showingEdit = true
+			}
+
+		case key.Matches(msg, m.keys.Export):
+			if targets := m.targets(); len(targets) > 0 && !m.inTrash() {
+				// Save current cursor position before opening dialog
+				m.savedCursor = m.list.Index()
+				m.editDialog.Show(targets, edit.FieldExport)
+				m.showingEdit = true
+			}
+
+		case key.Matches(msg, m.keys.Sort):
+			if m.sortOrder == svc.SortByFrecency {
+				m.sortOrder = svc.SortByCategory
+			} else {
+				m.sortOrder = svc.SortByFrecency
+			}
+			m.updateTitle()
+			return m, m.LoadBookmarks()
+
 		case key.Matches(msg, m.keys.Enter):
+			if selectedItem, ok := m.list.SelectedItem().(bookmarkItem); ok && !m.inTrash() {
+				if m.printMode {
+					cmd = m.pickBookmarks(m.targets())
+					return m, cmd
+				}
+				return m, m.openFolder(selectedItem.bookmark)
+			}
+
+		case key.Matches(msg, m.keys.Mark):
 			if selectedItem, ok := m.list.SelectedItem().(bookmarkItem); ok {
-				return m, m.openFolder(selectedItem.bookmark.Folder)
+				id := selectedItem.bookmark.ID
+				m.marked[id] = !m.marked[id]
+				if !m.marked[id] {
+					delete(m.marked, id)
+				}
+				selectedItem.marked = m.marked[id]
+				cmd = m.list.SetItem(m.list.Index(), selectedItem)
+				m.list.CursorDown()
+				m.updateTitle()
+				return m, cmd
+			}
+
+		case key.Matches(msg, m.keys.SelectAll):
+			return m, m.selectAll()
+
+		case key.Matches(msg, m.keys.RenameCat):
+			category, problem := m.tabCategory()
+			if problem != "" {
+				cmd = m.status.Info(problem)
+				return m, cmd
+			}
+			// Save current cursor position before opening dialog
+			m.savedCursor = m.list.Index()
+			m.editDialog.ShowRename(category)
+			m.showingEdit = true
+
+		case key.Matches(msg, m.keys.RemoveCat):
+			category, problem := m.tabCategory()
+			if problem != "" {
+				cmd = m.status.Info(problem)
+				return m, cmd
+			}
+			// Save current cursor position before opening dialog
+			m.savedCursor = m.list.Index()
+			m.confirming = confirmRemoveCategory
+			m.category = category
+			members := m.categoryMembers(category)
+			m.confirmDialog.ShowAction(members,
+				fmt.Sprintf("Remove category %q from %s?", category, describe(members)),
+				"Keep the bookmarks without it")
+			m.showingDialog = true
+			// Immediately send the current window size to the confirm dialog
+			if m.windowSize.Width > 0 && m.windowSize.Height > 0 {
+				m.confirmDialog, _ = m.confirmDialog.Update(m.windowSize)
+			}
+		}
+
+	case tea.MouseMsg:
+		// Clicking a tab switches to it; the mouse is only enabled fullscreen,
+		// where the tab bar is the first line below the margin
+		top, _, _, left := docStyle.GetMargin()
+		if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft && msg.Y == top {
+			if i := m.tabs().tabAt(msg.X - left); i >= 0 {
+				return m, m.selectTab(i)
 			}
 		}
+		return m, nil
 
 	case bookmarksLoadedMsg:
 		m.allBookmarks = msg.bookmarks
+		m.trashed = msg.trashed
 		m.categories = msg.categories
+		m.counts = msg.counts
 
-		// Set initial category if not already set
-		if m.activeCategory == "" {
-			m.activeCategory = "All"
+		// If the active tab is gone, with its category or the last bookmark in
+		// the trash, switch to all bookmarks
+		if !slices.Contains(m.tabList(), m.currentTab()) {
+			m.activeTab = allTab
+			m.activeCategory = ""
 		}
 
-		// Check if the current active category still exists
-		categoryExists := false
-		for _, cat := range m.categories {
-			if cat == m.activeCategory {
-				categoryExists = true
-				break
+		// Forget the selection of bookmarks that are gone
+		known := make(map[uint]bool, len(m.allBookmarks)+len(m.trashed))
+		for _, bookmarks := range [][]*models.Bookmark{m.allBookmarks, m.trashed} {
+			for _, b := range bookmarks {
+				known[b.ID] = true
 			}
 		}
-
-		// If the current category no longer exists, switch to "All"
-		if !categoryExists {
-			m.activeCategory = "All"
+		for id := range m.marked {
+			if !known[id] {
+				delete(m.marked, id)
+			}
 		}
 
 		// Update list title to show current category
-		m.list.Title = m.activeCategory
+		m.updateTitle()
 
-		return m, m.filterByCategory()
+		return m, tea.Batch(m.applyFilter(), m.checkFolders())
+
+	case foldersCheckedMsg:
+		for id, status := range msg.status {
+			m.folderStatus[id] = status
+			delete(m.checking, id)
+		}
+
+		// Flag the folders shown
+		items := m.list.Items()
+		for i, item := range items {
+			bookmarkItem := item.(bookmarkItem)
+			bookmarkItem.status = m.folderStatus[bookmarkItem.bookmark.ID]
+			items[i] = bookmarkItem
+		}
+		return m, m.list.SetItems(items)
 
 	case bookmarksFilteredMsg:
-		m.bookmarks = msg.bookmarks
+		m.bookmarks = make([]*models.Bookmark, len(msg.matches))
 
 		// Convert to list items
-		items := make([]list.Item, len(m.bookmarks))
-		for i, b := range m.bookmarks {
-			items[i] = bookmarkItem{bookmark: b}
+		items := make([]list.Item, len(msg.matches))
+		for i, match := range msg.matches {
+			b := match.Bookmark
+			m.bookmarks[i] = b
+			items[i] = bookmarkItem{
+				bookmark:      b,
+				status:        m.folderStatus[b.ID],
+				marked:        m.marked[b.ID],
+				folderMatches: match.FolderPositions,
+				tagMatches:    match.TagPositions,
+				aliasMatches:  match.AliasPositions,
+			}
 		}
 
 		m.list.SetItems(items)
 
-	case bookmarkDeletedMsg:
-		return m, m.LoadBookmarks() // Reload bookmarks
+	case bookmarksDeletedMsg:
+		m.history.Record(m.deleteEntry(msg.bookmarks))
+		m.unmark(msg.bookmarks)
+		toast := m.status.Success("Moved to trash: " + describe(msg.bookmarks))
+		return m, tea.Batch(m.LoadBookmarks(), toast) // Reload bookmarks
+
+	case categoriesUpdatedMsg:
+		m.history.Record(m.categoryEntry(msg.bookmarks, msg.previous, msg.category))
+		m.unmark(msg.bookmarks)
+		toast := m.status.Success(fmt.Sprintf("Category of %s set to %q", describe(msg.bookmarks), msg.category))
+		return m, tea.Batch(m.LoadBookmarks(), toast) // Reload bookmarks
+
+	case categoryRenamedMsg:
+		count := countBookmarks(len(msg.previous))
+		description := fmt.Sprintf("rename category %s → %s", msg.from, msg.to)
+		message := fmt.Sprintf("Renamed category %s to %s (%s)", msg.from, msg.to, count)
+		if msg.merged {
+			description = fmt.Sprintf("merge category %s into %s", msg.from, msg.to)
+			message = fmt.Sprintf("Merged category %s into %s (%s)", msg.from, msg.to, count)
+		}
+		m.history.Record(m.labelsEntry(description, msg.previous))
+		// Stay on the tab of the bookmarks
+		if m.currentTab() == (tab{kind: categoryTab, category: msg.from}) {
+			m.activeCategory = msg.to
+		}
+		toast := m.status.Success(message)
+		return m, tea.Batch(m.LoadBookmarks(), toast) // Reload bookmarks
+
+	case categoryRemovedMsg:
+		m.history.Record(m.labelsEntry("remove category "+msg.category, msg.previous))
+		toast := m.status.Success(fmt.Sprintf("Removed category %s from %s", msg.category, countBookmarks(len(msg.previous))))
+		return m, tea.Batch(m.LoadBookmarks(), toast) // Reload bookmarks
+
+	case folderOpenedMsg:
+		return m, m.LoadBookmarks() // Reload the visit count
+
+	case bookmarksExportedMsg:
+		cmd = m.status.Success(fmt.Sprintf("Exported %s to %s", describe(msg.bookmarks), msg.path))
+		return m, cmd
+
+	case aliasUpdatedMsg:
+		m.history.Record(m.aliasEntry(msg.bookmark, msg.oldAlias, msg.newAlias))
+		message := fmt.Sprintf("Alias of %s set to %s%s", msg.bookmark.Folder, models.AliasPrefix, msg.newAlias)
+		if msg.newAlias == "" {
+			message = "Alias removed from " + msg.bookmark.Folder
+		}
+		toast := m.status.Success(message)
+		return m, tea.Batch(m.LoadBookmarks(), toast) // Reload bookmarks
+
+	case bookmarksRestoredMsg:
+		m.history.Record(m.restoreEntry(msg.bookmarks))
+		m.unmark(msg.bookmarks)
+		toast := m.status.Success("Restored: " + describe(msg.bookmarks))
+		return m, tea.Batch(m.LoadBookmarks(), toast) // Reload bookmarks
+
+	case historyAppliedMsg:
+		if msg.err != nil {
+			// Put the entry back where it came from so it can be retried
+			if msg.undo {
+				m.history.Redo()
+			} else {
+				m.history.Undo()
+			}
+			m.err = msg.err
+			if msg.undo {
+				m.status.Error("Failed to undo "+msg.entry.Description, msg.err)
+			} else {
+				m.status.Error("Failed to redo "+msg.entry.Description, msg.err)
+			}
+			return m, nil
+		}
+		var toast tea.Cmd
+		if msg.undo {
+			toast = m.status.Success("Undid: " + msg.entry.Description)
+		} else {
+			toast = m.status.Success("Redid: " + msg.entry.Description)
+		}
+		return m, tea.Batch(m.LoadBookmarks(), toast) // Reload bookmarks
 
-	case bookmarkUpdatedMsg:
-		return m, m.LoadBookmarks() // Reload bookmarks
+	case bookmarksPurgedMsg:
+		m.unmark(msg.bookmarks)
+		toast := m.status.Success("Permanently deleted: " + describe(msg.bookmarks))
+		return m, tea.Batch(m.LoadBookmarks(), toast) // Reload bookmarks
 
 	case errMsg:
 		m.err = msg.err
+		m.status.Error(msg.action, msg.err)
 	}
 
 	// Update list
@@ -372,8 +710,73 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// updateDialog passes a message to the open dialog, acting on its result
+func (m Model) updateDialog(msg tea.Msg) (Model, tea.Cmd) {
+	if m.showingDialog {
+		newConfirm, confirmCmd := m.confirmDialog.Update(msg)
+		m.confirmDialog = newConfirm
+
+		// Check if user made a choice
+		if m.confirmDialog.HasResult() {
+			m.showingDialog = false
+			result := m.confirmDialog.GetResult()
+			if result.Confirmed && len(result.Bookmarks) > 0 {
+				switch m.confirming {
+				case confirmPurge:
+					return m, m.purgeBookmarks(result.Bookmarks)
+				case confirmRemoveCategory:
+					return m, m.removeCategory(m.category)
+				}
+				return m, m.deleteBookmarks(result.Bookmarks)
+			}
+			// Dialog was cancelled - restore saved cursor position
+			m.list.Select(m.savedCursor)
+			return m, nil
+		}
+
+		return m, confirmCmd
+	}
+
+	// Handle edit dialog
+	if m.showingEdit {
+		newEdit, editCmd := m.editDialog.Update(msg)
+		m.editDialog = newEdit
+
+		// Check if user made a choice
+		if m.editDialog.HasResult() {
+			m.showingEdit = false
+			result := m.editDialog.GetResult()
+			if result.Submitted {
+				switch result.Field {
+				case edit.FieldAlias:
+					return m, m.updateBookmarkAlias(result.Bookmarks[0], result.Value)
+				case edit.FieldExport:
+					return m, m.exportBookmarks(result.Bookmarks, result.Value)
+				case edit.FieldRename:
+					if result.Value != result.Category {
+						return m, m.renameCategory(result.Category, result.Value)
+					}
+					return m, nil
+				}
+				return m, m.updateCategories(result.Bookmarks, result.Value)
+			}
+			// Dialog was cancelled - restore saved cursor position
+			m.list.Select(m.savedCursor)
+			return m, nil
+		}
+
+		return m, editCmd
+	}
+
+	return m, nil
+}
+
 // View renders the interface
 func (m Model) View() string {
+	if m.quitting {
+		return ""
+	}
+
 	if m.showingDialog {
 		return m.confirmDialog.View()
 	}
@@ -382,6 +785,8 @@ func (m Model) View() string {
 		return m.editDialog.View()
 	}
 
+	tabsView := m.tabs().view + "\n\n"
+
 	// Render filter if focused or has value
 	var filterView string
 	if m.filterFocused {
@@ -392,152 +797,685 @@ func (m Model) View() string {
 		filterView = filterStyle.Render("Filter: "+m.filter.View()) + "\n"
 	}
 
-	return docStyle.Render(filterView + m.list.View())
+	h, _ := docStyle.GetFrameSize()
+	statusView := m.status.View(m.windowSize.Width - h)
+
+	return docStyle.Render(tabsView + filterView + m.list.View() + statusView)
 }
 
 // Helper functions
 
 func (m *Model) nextCategory() tea.Cmd {
-	for i, cat := range m.categories {
-		if cat == m.activeCategory {
-			nextIndex := (i + 1) % len(m.categories)
-			m.activeCategory = m.categories[nextIndex]
-			m.list.Title = m.activeCategory // Update list title
-			break
-		}
-	}
-	return m.filterByCategory()
+	tabs := m.tabList()
+	i := slices.Index(tabs, m.currentTab())
+	return m.selectTab((i + 1) % len(tabs))
 }
 
 func (m *Model) prevCategory() tea.Cmd {
-	for i, cat := range m.categories {
-		if cat == m.activeCategory {
-			prevIndex := (i - 1 + len(m.categories)) % len(m.categories)
-			m.activeCategory = m.categories[prevIndex]
-			m.list.Title = m.activeCategory // Update list title
-			break
+	tabs := m.tabList()
+	i := slices.Index(tabs, m.currentTab())
+	return m.selectTab((i - 1 + len(tabs)) % len(tabs))
+}
+
+// selectTab switches to the tab at index i of the tab list
+func (m *Model) selectTab(i int) tea.Cmd {
+	t := m.tabList()[i]
+	m.activeTab = t.kind
+	m.activeCategory = t.category
+	m.updateTitle()
+	return m.filterByCategory()
+}
+
+// tabList returns the tabs shown: all bookmarks, each category, and the trash
+// when there is something in it
+func (m Model) tabList() []tab {
+	tabs := make([]tab, 0, len(m.categories)+2)
+	tabs = append(tabs, tab{kind: allTab})
+	for _, category := range m.categories {
+		tabs = append(tabs, tab{kind: categoryTab, category: category})
+	}
+	if len(m.trashed) > 0 {
+		tabs = append(tabs, tab{kind: trashTab})
+	}
+	return tabs
+}
+
+// currentTab returns the active tab
+func (m Model) currentTab() tab {
+	if m.activeTab != categoryTab {
+		return tab{kind: m.activeTab}
+	}
+	return tab{kind: categoryTab, category: m.activeCategory}
+}
+
+// tabs renders the tab bar for the window width
+func (m Model) tabs() tabBar {
+	tabs := m.tabList()
+	labels := make([]string, len(tabs))
+	active := 0
+	for i, t := range tabs {
+		switch t.kind {
+		case allTab:
+			labels[i] = tabLabel(allTabName, len(m.allBookmarks))
+		case trashTab:
+			labels[i] = tabLabel(trashTabName, len(m.trashed))
+		default:
+			labels[i] = tabLabel(t.category, m.counts[t.category])
+		}
+		if t == m.currentTab() {
+			active = i
 		}
 	}
-	return m.filterByCategory()
+
+	h, _ := docStyle.GetFrameSize()
+	return renderTabs(labels, active, m.tabOffset, m.windowSize.Width-h)
 }
 
 func (m *Model) filterByCategory() tea.Cmd {
 	return func() tea.Msg {
-		var filtered []*models.Bookmark
+		return bookmarksFilteredMsg{matches: svc.FuzzyFilter(m.categoryBookmarks(), "")}
+	}
+}
 
-		if m.activeCategory == "All" {
-			filtered = m.allBookmarks
-		} else {
-			for _, b := range m.allBookmarks {
-				if string(b.Category) == m.activeCategory {
-					filtered = append(filtered, b)
-				}
-			}
-		}
+// categoryBookmarks returns the bookmarks shown in the active tab
+func (m *Model) categoryBookmarks() []*models.Bookmark {
+	switch m.activeTab {
+	case allTab:
+		return m.allBookmarks
+	case trashTab:
+		return m.trashed
+	}
 
-		return bookmarksFilteredMsg{bookmarks: filtered}
+	var filtered []*models.Bookmark
+	for _, b := range m.allBookmarks {
+		if b.HasTag(m.activeCategory) {
+			filtered = append(filtered, b)
+		}
 	}
+	return filtered
+}
+
+// inTrash reports whether the trash tab is active
+func (m *Model) inTrash() bool {
+	return m.activeTab == trashTab
 }
 
 func (m *Model) applyFilter() tea.Cmd {
 	return func() tea.Msg {
-		filterText := strings.ToLower(strings.TrimSpace(m.filter.Value()))
+		// Filter the active category, best matches first
+		matches := svc.FuzzyFilter(m.categoryBookmarks(), m.filter.Value())
+		return bookmarksFilteredMsg{matches: matches}
+	}
+}
 
-		var filtered []*models.Bookmark
-		source := m.allBookmarks
+// checkFolders checks the folders of bookmarks on disk so missing ones can be
+// flagged. It runs in the background, as folders on network mounts can be
+// slow, and once per bookmark: the results are kept across reloads.
+func (m *Model) checkFolders() tea.Cmd {
+	folders := make(map[uint]string)
+	for _, b := range m.allBookmarks {
+		if _, checked := m.folderStatus[b.ID]; !checked && !m.checking[b.ID] {
+			folders[b.ID] = b.Folder
+			m.checking[b.ID] = true
+		}
+	}
+	if len(folders) == 0 {
+		return nil
+	}
 
-		// First filter by category
-		if m.activeCategory != "All" {
-			source = []*models.Bookmark{}
-			for _, b := range m.allBookmarks {
-				if string(b.Category) == m.activeCategory {
-					source = append(source, b)
-				}
-			}
+	return func() tea.Msg {
+		status := make(map[uint]svc.FolderStatus, len(folders))
+		for id, folder := range folders {
+			status[id] = m.folderService.CheckFolder(folder)
 		}
+		return foldersCheckedMsg{status: status}
+	}
+}
 
-		// Then apply text filter
-		if filterText == "" {
-			filtered = source
-		} else {
-			for _, b := range source {
-				if strings.Contains(strings.ToLower(b.Folder), filterText) ||
-					strings.Contains(strings.ToLower(string(b.Category)), filterText) {
-					filtered = append(filtered, b)
-				}
-			}
+func (m *Model) deleteBookmarks(bookmarks []*models.Bookmark) tea.Cmd {
+	return func() tea.Msg {
+		if err := m.bookmarkService.DeleteByIDs(bookmarkIDs(bookmarks)); err != nil {
+			return errMsg{"Failed to delete " + describe(bookmarks), err}
 		}
+		return bookmarksDeletedMsg{bookmarks: bookmarks}
+	}
+}
 
-		return bookmarksFilteredMsg{bookmarks: filtered}
+func (m *Model) restoreBookmarks(bookmarks []*models.Bookmark) tea.Cmd {
+	return func() tea.Msg {
+		if _, err := m.bookmarkService.RestoreByIDs(bookmarkIDs(bookmarks)); err != nil {
+			return errMsg{"Failed to restore " + describe(bookmarks), err}
+		}
+		return bookmarksRestoredMsg{bookmarks: bookmarks}
 	}
 }
 
-func (m *Model) deleteBookmark(b *models.Bookmark) tea.Cmd {
+func (m *Model) purgeBookmarks(bookmarks []*models.Bookmark) tea.Cmd {
 	return func() tea.Msg {
-		if err := m.bookmarkService.Delete(b); err != nil {
-			return errMsg{err}
+		if err := m.bookmarkService.PurgeByIDs(bookmarkIDs(bookmarks)); err != nil {
+			return errMsg{"Failed to permanently delete " + describe(bookmarks), err}
 		}
-		return bookmarkDeletedMsg{}
+		return bookmarksPurgedMsg{bookmarks: bookmarks}
 	}
 }
 
-func (m *Model) updateBookmarkCategory(b *models.Bookmark, newCategory string) tea.Cmd {
+func (m *Model) updateCategories(bookmarks []*models.Bookmark, category string) tea.Cmd {
 	return func() tea.Msg {
-		// Update the bookmark's category
-		b.Category = models.CategoryType(newCategory)
+		categories := make(map[uint]models.CategoryType, len(bookmarks))
+		for _, b := range bookmarks {
+			categories[b.ID] = models.CategoryType(category)
+		}
 
-		// Save the updated bookmark
-		if err := m.bookmarkService.Save(b); err != nil {
-			return errMsg{err}
+		previous, err := m.bookmarkService.SetCategories(categories)
+		if err != nil {
+			return errMsg{"Failed to save " + describe(bookmarks), err}
+		}
+		return categoriesUpdatedMsg{
+			bookmarks: bookmarks,
+			previous:  previous,
+			category:  category,
+		}
+	}
+}
+
+func (m *Model) renameCategory(from, to string) tea.Cmd {
+	// Naming the category like another one merges them
+	merged := len(m.categoryMembers(strings.TrimSpace(to))) > 0
+	return func() tea.Msg {
+		previous, err := m.bookmarkService.MergeCategories([]string{from}, to)
+		if err != nil {
+			return errMsg{"Failed to rename category " + from, err}
+		}
+		return categoryRenamedMsg{
+			from:     from,
+			to:       strings.TrimSpace(to),
+			merged:   merged,
+			previous: previous,
+		}
+	}
+}
+
+func (m *Model) removeCategory(category string) tea.Cmd {
+	return func() tea.Msg {
+		previous, err := m.bookmarkService.RemoveCategories(category)
+		if err != nil {
+			return errMsg{"Failed to remove category " + category, err}
 		}
-		return bookmarkUpdatedMsg{}
+		return categoryRemovedMsg{category: category, previous: previous}
 	}
 }
 
-func (m *Model) openFolder(path string) tea.Cmd {
+func (m *Model) exportBookmarks(bookmarks []*models.Bookmark, path string) tea.Cmd {
 	return func() tea.Msg {
-		// In cwd-file mode, write the path to file and quit
+		if err := svc.ExportFile(path, bookmarks); err != nil {
+			return errMsg{"Failed to export " + describe(bookmarks), err}
+		}
+		return bookmarksExportedMsg{bookmarks: bookmarks, path: path}
+	}
+}
+
+func (m *Model) updateBookmarkAlias(b *models.Bookmark, newAlias string) tea.Cmd {
+	// The alias is saved on a copy, as the list being rendered shares the
+	// bookmark; it shows the new alias after reloading
+	updated := *b
+	updated.Alias = models.NormalizeAlias(newAlias)
+	return func() tea.Msg {
+		if err := m.bookmarkService.Save(&updated); err != nil {
+			return errMsg{"Failed to set the alias of " + updated.Folder, err}
+		}
+		return aliasUpdatedMsg{
+			bookmark: &updated,
+			oldAlias: b.Alias,
+			newAlias: updated.Alias,
+		}
+	}
+}
+
+func (m *Model) openFolder(b *models.Bookmark) tea.Cmd {
+	// The visit is recorded on a copy, as the list being rendered shares the
+	// bookmark; it shows the new count after reloading
+	visited := *b
+	return func() tea.Msg {
+		// Record the visit for frecency ranking; a failure here should not
+		// prevent the folder from being opened
+		visitErr := m.bookmarkService.RecordVisit(&visited)
+
+		// In cwd-file mode, write the path to file and quit; a failed visit
+		// is reported once the TUI is gone
 		if m.cwdFile != "" {
-			if err := m.folderService.WriteCwdFile(m.cwdFile, path); err != nil {
-				return errMsg{err}
+			if err := m.folderService.WriteCwdFile(m.cwdFile, b.Folder); err != nil {
+				return errMsg{"Failed to write the selected folder", err}
 			}
-			return tea.Quit()
+			if visitErr != nil {
+				return quitMsg{fmt.Errorf("failed to record the visit to %s: %w", b.Folder, visitErr)}
+			}
+			return quitMsg{}
 		}
 
 		// Normal mode: open in file manager
-		if err := m.folderService.OpenInFileManager(path); err != nil {
-			return errMsg{err}
+		if err := m.folderService.OpenInFileManager(b.Folder); err != nil {
+			return errMsg{"Failed to open " + b.Folder, err}
 		}
 
-		return nil
+		if visitErr != nil {
+			return errMsg{"Failed to record the visit to " + b.Folder, visitErr}
+		}
+
+		return folderOpenedMsg{}
+	}
+}
+
+// selected returns the selected bookmarks in list order, those in the trash
+// when the trash tab is active and the others otherwise
+func (m *Model) selected() []*models.Bookmark {
+	source := m.allBookmarks
+	if m.inTrash() {
+		source = m.trashed
+	}
+
+	var selected []*models.Bookmark
+	for _, b := range source {
+		if m.marked[b.ID] {
+			selected = append(selected, b)
+		}
+	}
+	return selected
+}
+
+// targets returns the bookmarks an action applies to: the selected ones, or
+// the current bookmark when none are selected
+func (m *Model) targets() []*models.Bookmark {
+	if selected := m.selected(); len(selected) > 0 {
+		return selected
+	}
+	if selectedItem, ok := m.list.SelectedItem().(bookmarkItem); ok {
+		return []*models.Bookmark{selectedItem.bookmark}
+	}
+	return nil
+}
+
+// selectAll selects the bookmarks shown, or clears the selection when they
+// are all selected already
+func (m *Model) selectAll() tea.Cmd {
+	items := m.list.Items()
+	all := true
+	for _, item := range items {
+		if !m.marked[item.(bookmarkItem).bookmark.ID] {
+			all = false
+			break
+		}
+	}
+
+	if all {
+		clear(m.marked)
+	}
+	for i, item := range items {
+		selectedItem := item.(bookmarkItem)
+		if !all {
+			m.marked[selectedItem.bookmark.ID] = true
+		}
+		selectedItem.marked = m.marked[selectedItem.bookmark.ID]
+		items[i] = selectedItem
+	}
+
+	m.updateTitle()
+	return m.list.SetItems(items)
+}
+
+// tabCategory returns the category of the active tab, or a message
+// explaining why there is no category to act on
+func (m *Model) tabCategory() (category, problem string) {
+	if m.activeTab != categoryTab {
+		return "", "Switch to the tab of a category first"
+	}
+	return m.activeCategory, ""
+}
+
+// categoryMembers returns the bookmarks shown that are in the category
+func (m *Model) categoryMembers(category string) []*models.Bookmark {
+	var members []*models.Bookmark
+	for _, b := range m.allBookmarks {
+		if b.HasTag(category) {
+			members = append(members, b)
+		}
+	}
+	return members
+}
+
+// unmark removes bookmarks from the selection
+func (m *Model) unmark(bookmarks []*models.Bookmark) {
+	for _, b := range bookmarks {
+		delete(m.marked, b.ID)
 	}
 }
 
+// bookmarkIDs returns the IDs of the bookmarks
+func bookmarkIDs(bookmarks []*models.Bookmark) []uint {
+	ids := make([]uint, len(bookmarks))
+	for i, b := range bookmarks {
+		ids[i] = b.ID
+	}
+	return ids
+}
+
+// describe names the bookmarks in messages: the folder of a single bookmark,
+// or how many there are
+func describe(bookmarks []*models.Bookmark) string {
+	if len(bookmarks) == 1 {
+		return bookmarks[0].Folder
+	}
+	return countBookmarks(len(bookmarks))
+}
+
+// countBookmarks returns "1 bookmark" or "<n> bookmarks"
+func countBookmarks(n int) string {
+	if n == 1 {
+		return "1 bookmark"
+	}
+	return fmt.Sprintf("%d bookmarks", n)
+}
+
+// pickBookmarks records visits to the chosen bookmarks and quits, leaving them
+// in Selection for the caller to print
+func (m *Model) pickBookmarks(picked []*models.Bookmark) tea.Cmd {
+	m.selection = picked
+	return func() tea.Msg {
+		// Failing to record a visit should not lose the selection; it is
+		// reported once the TUI is gone
+		var errs []error
+		for _, b := range picked {
+			visited := *b
+			if err := m.bookmarkService.RecordVisit(&visited); err != nil {
+				errs = append(errs, fmt.Errorf("failed to record the visit to %s: %w", b.Folder, err))
+			}
+		}
+		return quitMsg{errors.Join(errs...)}
+	}
+}
+
+// applyHistory runs the undo or redo side of a history entry
+func (m *Model) applyHistory(entry history.Entry, undo bool) tea.Cmd {
+	return func() tea.Msg {
+		run := entry.Redo
+		if undo {
+			run = entry.Undo
+		}
+		return historyAppliedMsg{entry: entry, undo: undo, err: run()}
+	}
+}
+
+// deleteEntry records moving bookmarks to the trash, undone by restoring them
+func (m *Model) deleteEntry(bookmarks []*models.Bookmark) history.Entry {
+	ids := bookmarkIDs(bookmarks)
+	return history.Entry{
+		Description: "delete " + describe(bookmarks),
+		Undo: func() error {
+			_, err := m.bookmarkService.RestoreByIDs(ids)
+			return err
+		},
+		Redo: func() error {
+			return m.bookmarkService.DeleteByIDs(ids)
+		},
+	}
+}
+
+// restoreEntry records a restore from the trash, undone by deleting again
+func (m *Model) restoreEntry(bookmarks []*models.Bookmark) history.Entry {
+	deleted := m.deleteEntry(bookmarks)
+	return history.Entry{
+		Description: "restore " + describe(bookmarks),
+		Undo:        deleted.Redo,
+		Redo:        deleted.Undo,
+	}
+}
+
+// categoryEntry records a category change of bookmarks
+func (m *Model) categoryEntry(bookmarks []*models.Bookmark, previous map[uint]svc.Labels, category string) history.Entry {
+	description := fmt.Sprintf("category of %s → %q", describe(bookmarks), category)
+	if len(bookmarks) == 1 {
+		description = fmt.Sprintf("category of %s %q → %q", bookmarks[0].Folder, previous[bookmarks[0].ID].Category, category)
+	}
+	return m.labelsEntry(description, previous)
+}
+
+// labelsEntry records a change of the categories and tags of bookmarks,
+// undone by setting their previous labels again; undoing returns the labels
+// the change left, which redo it
+func (m *Model) labelsEntry(description string, previous map[uint]svc.Labels) history.Entry {
+	var next map[uint]svc.Labels
+	return history.Entry{
+		Description: description,
+		Undo: func() (err error) {
+			next, err = m.bookmarkService.SetLabels(previous)
+			return err
+		},
+		Redo: func() error {
+			_, err := m.bookmarkService.SetLabels(next)
+			return err
+		},
+	}
+}
+
+// aliasEntry records an alias change
+func (m *Model) aliasEntry(b *models.Bookmark, oldAlias, newAlias string) history.Entry {
+	id := b.ID
+	setAlias := func(alias string) error {
+		// Reload so the change applies to the current state of the bookmark
+		current, err := m.bookmarkService.GetByID(id)
+		if err != nil {
+			return err
+		}
+		current.Alias = alias
+		return m.bookmarkService.Save(current)
+	}
+
+	return history.Entry{
+		Description: fmt.Sprintf("alias of %s %q → %q", b.Folder, oldAlias, newAlias),
+		Undo:        func() error { return setAlias(oldAlias) },
+		Redo:        func() error { return setAlias(newAlias) },
+	}
+}
+
+// resize fits the list into the window, leaving room for the filter and
+// the notification area
+func (m *Model) resize() {
+	if m.windowSize.Width == 0 || m.windowSize.Height == 0 {
+		return
+	}
+
+	// The tab bar takes two lines and the filter four when shown; one line is
+	// always kept for notifications so toasts do not make the list jump
+	reserved := 2 + 4 + max(1, m.status.Height())
+	h, v := docStyle.GetFrameSize()
+	m.list.SetSize(m.windowSize.Width-h, m.windowSize.Height-v-reserved)
+}
+
+// updateTitle sets the list title from the display modes; the active category
+// is shown by the tab bar
+func (m *Model) updateTitle() {
+	title := "Bookmarks"
+	if m.inTrash() {
+		title = trashTabName
+	}
+	if m.sortOrder == svc.SortByFrecency {
+		title += " · frecency"
+	}
+	if !m.query.Empty() {
+		title += " · " + m.query.String()
+	}
+	if selected := len(m.selected()); selected > 0 {
+		title += fmt.Sprintf(" · %d selected", selected)
+	}
+	if m.cwdFile != "" || m.printMode {
+		title += " (Select Mode)"
+	}
+	m.list.Title = title
+}
+
 // Messages
 type bookmarksLoadedMsg struct {
 	bookmarks  []*models.Bookmark
+	trashed    []*models.Bookmark
 	categories []string
+	counts     map[string]int
+}
+
+// foldersCheckedMsg reports the state of folders on disk by bookmark ID
+type foldersCheckedMsg struct {
+	status map[uint]svc.FolderStatus
 }
 
 type bookmarksFilteredMsg struct {
+	matches []svc.FilterMatch
+}
+
+type bookmarksDeletedMsg struct {
+	bookmarks []*models.Bookmark
+}
+
+type categoriesUpdatedMsg struct {
+	bookmarks []*models.Bookmark
+	previous  map[uint]svc.Labels
+	category  string
+}
+
+type aliasUpdatedMsg struct {
+	bookmark *models.Bookmark
+	oldAlias string
+	newAlias string
+}
+
+type bookmarksRestoredMsg struct {
+	bookmarks []*models.Bookmark
+}
+
+type categoryRenamedMsg struct {
+	from     string
+	to       string
+	merged   bool
+	previous map[uint]svc.Labels
+}
+
+type categoryRemovedMsg struct {
+	category string
+	previous map[uint]svc.Labels
+}
+
+type bookmarksExportedMsg struct {
 	bookmarks []*models.Bookmark
+	path      string
 }
 
-type bookmarkDeletedMsg struct{}
+type historyAppliedMsg struct {
+	entry history.Entry
+	undo  bool
+	err   error
+}
+
+type bookmarksPurgedMsg struct {
+	bookmarks []*models.Bookmark
+}
+
+// folderOpenedMsg reports a folder opened in the file manager, with its visit
+// recorded
+type folderOpenedMsg struct{}
 
-type bookmarkUpdatedMsg struct{}
+// quitMsg exits the program once a command has finished its work, with a
+// warning about what failed on the way, if anything
+type quitMsg struct {
+	warning error
+}
 
+// errMsg reports a failed operation; action describes what was attempted
 type errMsg struct {
-	err error
+	action string
+	err    error
 }
 
 // SetCwdFile sets the cwd file path for the model
 func (m *Model) SetCwdFile(filepath string) {
 	m.cwdFile = filepath
-	if filepath != "" {
-		// Update the list title to indicate cwd-file mode
-		if m.list.Title != "" {
-			m.list.Title = m.list.Title + " (Select Mode)"
+	// Update the list title to indicate cwd-file mode
+	m.updateTitle()
+}
+
+// SetSortOrder sets the initial ordering of the bookmark list
+func (m *Model) SetSortOrder(order svc.SortOrder) {
+	m.sortOrder = order
+	m.updateTitle()
+}
+
+// SetQuery restricts the bookmarks shown to those matching the query
+func (m *Model) SetQuery(q *query.Query) {
+	m.query = q
+	m.updateTitle()
+}
+
+// SetPrintMode makes enter select bookmarks for the caller instead of opening
+// them; the chosen bookmarks are available from Selection after the program
+// exits. Space marks several bookmarks to select at once.
+func (m *Model) SetPrintMode(enabled bool) {
+	m.printMode = enabled
+	m.updateTitle()
+}
+
+// SetHeight limits the TUI to part of the terminal so it can be drawn inline
+// below the prompt instead of in the alternate screen
+func (m *Model) SetHeight(height Height) {
+	m.height = height
+}
+
+// SetKeys replaces the key bindings of the list and its dialogs. The help
+// view shows the keys bound.
+func (m *Model) SetKeys(bindings keys.Bindings) {
+	m.keys = newKeyMap(bindings)
+	m.list.KeyMap = listKeyMap(bindings.List)
+	m.confirmDialog.SetKeys(bindings.Confirm)
+	m.editDialog.SetKeys(bindings.Edit)
+	m.status.SetDismissKey(m.keys.Dismiss)
+
+	// Set up additional help keys that show in full help view; the bubbles
+	// list shows the navigation and quit bindings itself
+	km := m.keys
+	m.list.AdditionalFullHelpKeys = func() []key.Binding {
+		return []key.Binding{
+			km.NextTab,
+			km.PrevTab,
+			km.GotoTab,
+			km.Filter,
+			km.ClearFilter,
+			km.Enter,
+			km.Edit,
+			km.Alias,
+			km.Delete,
+			km.Sort,
+			km.Restore,
+			km.Undo,
+			km.Redo,
+			km.Mark,
+			km.SelectAll,
+			km.Export,
+			km.RenameCat,
+			km.RemoveCat,
+			km.Dismiss,
 		}
 	}
 }
+
+// SetOpener sets the command folders are opened with instead of the system
+// file manager, see service.Folders.SetOpener
+func (m *Model) SetOpener(opener []string) {
+	m.folderService.SetOpener(opener)
+}
+
+// Selection returns the bookmarks chosen in print mode, or nil when the user
+// quit without choosing
+func (m Model) Selection() []*models.Bookmark {
+	return m.selection
+}
+
+// Warning returns what failed without stopping the TUI as it quit, such as
+// recording the visit to the chosen folder, or nil
+func (m Model) Warning() error {
+	return m.warning
+}
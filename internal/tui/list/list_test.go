@@ -0,0 +1,258 @@
+package list
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jhoffmann/bookmark-manager/internal/models"
+	svc "github.com/jhoffmann/bookmark-manager/internal/service"
+	"github.com/jhoffmann/bookmark-manager/internal/tui/edit"
+)
+
+// loaded returns a model showing the bookmarks, without a database
+func loaded(t *testing.T, bookmarks, trashed []*models.Bookmark) Model {
+	t.Helper()
+
+	m := New(nil, "")
+	m, _ = m.update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m, _ = m.update(bookmarksLoadedMsg{bookmarks: bookmarks, trashed: trashed})
+	m, _ = m.update(bookmarksFilteredMsg{matches: svc.FuzzyFilter(m.categoryBookmarks(), "")})
+	return m
+}
+
+func TestModel_Selection(t *testing.T) {
+	bookmarks := []*models.Bookmark{
+		{ID: 1, Folder: "/test/a"},
+		{ID: 2, Folder: "/test/b"},
+		{ID: 3, Folder: "/test/c"},
+	}
+	trashed := []*models.Bookmark{{ID: 4, Folder: "/test/d"}}
+	m := loaded(t, bookmarks, trashed)
+
+	// Without a selection, actions apply to the current bookmark
+	if got := m.targets(); len(got) != 1 || got[0].ID != 1 {
+		t.Fatalf("targets() = %v, want the current bookmark", got)
+	}
+
+	// Space selects and moves down
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	m, _ = m.update(space)
+	m, _ = m.update(space)
+	if got := m.targets(); len(got) != 2 || got[0].ID != 1 || got[1].ID != 2 {
+		t.Fatalf("targets() = %v, want /test/a and /test/b", got)
+	}
+	if want := "Bookmarks · 2 selected"; m.list.Title != want {
+		t.Errorf("Title = %q, want %q", m.list.Title, want)
+	}
+
+	// Select all selects the rest, then clears the selection
+	m.selectAll()
+	if got := m.targets(); len(got) != 3 {
+		t.Fatalf("targets() = %v, want all bookmarks", got)
+	}
+	for _, item := range m.list.Items() {
+		if !item.(bookmarkItem).marked {
+			t.Errorf("Expected %s to be shown as selected", item.(bookmarkItem).bookmark.Folder)
+		}
+	}
+	m.selectAll()
+	if len(m.marked) != 0 {
+		t.Errorf("Expected select all to clear a full selection, got %v", m.marked)
+	}
+
+	// Bookmarks that are gone leave the selection when reloading
+	m.marked[2] = true
+	m.marked[3] = true
+	m, _ = m.update(bookmarksLoadedMsg{bookmarks: bookmarks[:2], trashed: trashed})
+	if len(m.marked) != 1 || !m.marked[2] {
+		t.Errorf("marked = %v, want only bookmark 2", m.marked)
+	}
+
+	// The trash tab has a selection of its own
+	m.activeTab = trashTab
+	m, _ = m.update(bookmarksFilteredMsg{matches: svc.FuzzyFilter(m.categoryBookmarks(), "")})
+	if got := m.targets(); len(got) != 1 || got[0].ID != 4 {
+		t.Errorf("targets() = %v, want the current trashed bookmark", got)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	one := []*models.Bookmark{{Folder: "/test/a"}}
+	if got := describe(one); got != "/test/a" {
+		t.Errorf("describe() = %q, want the folder", got)
+	}
+	if got := describe(append(one, &models.Bookmark{Folder: "/test/b"})); got != "2 bookmarks" {
+		t.Errorf("describe() = %q, want the count", got)
+	}
+}
+
+func TestModel_TabCategory(t *testing.T) {
+	bookmarks := []*models.Bookmark{
+		{ID: 1, Folder: "/test/a", Category: "work", Tags: []models.Tag{{Name: "work"}, {Name: "go"}}},
+		{ID: 2, Folder: "/test/b", Category: "work", Tags: []models.Tag{{Name: "work"}}},
+	}
+	m := loaded(t, bookmarks, nil)
+
+	for _, kind := range []tabKind{allTab, trashTab} {
+		m.activeTab = kind
+		if category, problem := m.tabCategory(); category != "" || problem == "" {
+			t.Errorf("tabCategory() on tab %d = %q, %q; want no category", kind, category, problem)
+		}
+	}
+
+	// Every tag is a category, primary or not
+	m.activeTab = categoryTab
+	for tab, want := range map[string]int{"work": 2, "go": 1} {
+		m.activeCategory = tab
+		if category, problem := m.tabCategory(); category != tab || problem != "" {
+			t.Errorf("tabCategory() = %q, %q; want %s", category, problem, tab)
+		}
+		if members := m.categoryMembers(tab); len(members) != want {
+			t.Errorf("categoryMembers(%q) = %v, want %d bookmarks", tab, members, want)
+		}
+	}
+}
+
+func TestModel_Tabs(t *testing.T) {
+	m := loaded(t, nil, nil)
+	m, _ = m.update(bookmarksLoadedMsg{
+		bookmarks:  []*models.Bookmark{{ID: 1}, {ID: 2}, {ID: 3}},
+		categories: []string{"go", "work"},
+		counts:     map[string]int{"go": 1, "work": 2},
+	})
+
+	if view := m.View(); !strings.Contains(view, "All (3)") || !strings.Contains(view, "work (2)") {
+		t.Errorf("View() = %q, want the tabs with their counts", view)
+	}
+
+	// Number keys go to the nth tab, if there is one
+	m, _ = m.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'3'}})
+	if m.activeCategory != "work" {
+		t.Errorf("activeCategory = %q after 3, want work", m.activeCategory)
+	}
+	m, _ = m.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'9'}})
+	if m.activeCategory != "work" {
+		t.Errorf("activeCategory = %q after 9, want it unchanged", m.activeCategory)
+	}
+
+	// Clicking a tab switches to it
+	zone := m.tabs().zones[1]
+	top, _, _, left := docStyle.GetMargin()
+	m, _ = m.update(tea.MouseMsg{X: left + zone.start + 1, Y: top, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	if m.activeCategory != "go" {
+		t.Errorf("activeCategory = %q after clicking its tab, want go", m.activeCategory)
+	}
+}
+
+func TestModel_TrashTab(t *testing.T) {
+	// A tag named like the trash tab is a category like any other
+	bookmarks := []*models.Bookmark{{ID: 1, Folder: "/test/a", Tags: []models.Tag{{Name: "Trash"}}}}
+	trashed := []*models.Bookmark{{ID: 2, Folder: "/test/b"}}
+	m := loaded(t, bookmarks, trashed)
+	m, _ = m.update(bookmarksLoadedMsg{
+		bookmarks:  bookmarks,
+		trashed:    trashed,
+		categories: []string{"Trash"},
+		counts:     map[string]int{"Trash": 1},
+	})
+
+	tabs := m.tabList()
+	want := []tab{{kind: allTab}, {kind: categoryTab, category: "Trash"}, {kind: trashTab}}
+	if !slices.Equal(tabs, want) {
+		t.Fatalf("tabList() = %v, want %v", tabs, want)
+	}
+
+	m.selectTab(1)
+	if m.inTrash() {
+		t.Error("Expected the Trash category not to be the trash")
+	}
+	if got := m.categoryBookmarks(); len(got) != 1 || got[0].ID != 1 {
+		t.Errorf("categoryBookmarks() = %v, want the tagged bookmark", got)
+	}
+
+	m.selectTab(2)
+	if got := m.categoryBookmarks(); !m.inTrash() || len(got) != 1 || got[0].ID != 2 {
+		t.Errorf("categoryBookmarks() = %v, want the trashed bookmark", got)
+	}
+
+	// Once the trash is empty its tab is gone, and all bookmarks are shown
+	m, _ = m.update(bookmarksLoadedMsg{bookmarks: bookmarks, categories: []string{"Trash"}})
+	if m.currentTab() != (tab{kind: allTab}) {
+		t.Errorf("currentTab() = %v after emptying the trash, want all bookmarks", m.currentTab())
+	}
+}
+
+func TestModel_FolderStatus(t *testing.T) {
+	missing := t.TempDir() + "/missing"
+	bookmarks := []*models.Bookmark{{ID: 1, Folder: t.TempDir()}, {ID: 2, Folder: missing}}
+	m := loaded(t, bookmarks, nil)
+
+	// Loading started a check of both folders, which is not repeated while it
+	// runs
+	if !m.checking[1] || !m.checking[2] {
+		t.Fatalf("checking = %v, want both bookmarks", m.checking)
+	}
+	if cmd := m.checkFolders(); cmd != nil {
+		t.Error("Expected no second check of folders being checked")
+	}
+
+	m.checking = make(map[uint]bool)
+	msg := m.checkFolders()()
+	m, _ = m.update(msg)
+	if got := m.list.Items()[1].(bookmarkItem).status; got != svc.FolderMissing {
+		t.Errorf("status = %q, want the missing folder flagged", got)
+	}
+	if len(m.checking) != 0 {
+		t.Errorf("checking = %v after the check, want none", m.checking)
+	}
+
+	// Reloads only check new bookmarks
+	bookmarks = append(bookmarks, &models.Bookmark{ID: 3, Folder: missing})
+	m, _ = m.update(bookmarksLoadedMsg{bookmarks: bookmarks})
+	if len(m.checking) != 1 || !m.checking[3] {
+		t.Errorf("checking = %v after reloading, want the new bookmark", m.checking)
+	}
+}
+
+func TestModel_UpdateBehindDialog(t *testing.T) {
+	bookmarks := []*models.Bookmark{{ID: 1, Folder: "/test/a"}, {ID: 2, Folder: "/test/b"}}
+	m := loaded(t, bookmarks[:1], nil)
+	m.editDialog.Show(bookmarks[:1], edit.FieldExport)
+	m.showingEdit = true
+
+	// Results arriving while the dialog is open are not lost
+	m, _ = m.update(errMsg{action: "Export failed", err: errors.New("disk full")})
+	m, _ = m.update(bookmarksLoadedMsg{bookmarks: bookmarks})
+	m, _ = m.update(bookmarksFilteredMsg{matches: svc.FuzzyFilter(m.categoryBookmarks(), "")})
+	if !m.showingEdit {
+		t.Fatal("Expected the dialog to stay open")
+	}
+	if m.err == nil {
+		t.Error("Expected the error to be kept")
+	}
+	if len(m.allBookmarks) != 2 || len(m.list.Items()) != 2 {
+		t.Errorf("Expected the reload to reach the list, got %d items", len(m.list.Items()))
+	}
+
+	// Keys still go to the dialog only
+	m, _ = m.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	if !m.showingEdit || m.quitting {
+		t.Error("Expected typing in the dialog not to act on the list")
+	}
+}
+
+func TestModel_QuitWarning(t *testing.T) {
+	m := loaded(t, []*models.Bookmark{{ID: 1, Folder: "/test/a"}}, nil)
+
+	warning := errors.New("database is locked")
+	model, cmd := m.Update(quitMsg{warning: warning})
+	if cmd == nil {
+		t.Fatal("Expected quitting")
+	}
+	if got := model.(Model).Warning(); !errors.Is(got, warning) {
+		t.Errorf("Warning() = %v, want the failure to report after quitting", got)
+	}
+}
@@ -0,0 +1,139 @@
+package list
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jhoffmann/bookmark-manager/internal/tui/styles"
+)
+
+// Markers shown at the ends of the tab bar when tabs are scrolled out of view
+const (
+	tabsBefore = "‹ "
+	tabsAfter  = " ›"
+)
+
+// tabKind tells the tabs of categories from the others, so that a category
+// can have any name
+type tabKind int
+
+const (
+	allTab      tabKind = iota // Every bookmark outside the trash
+	categoryTab                // The bookmarks of a category
+	trashTab                   // The bookmarks in the trash
+)
+
+// tab is a tab of the tab bar
+type tab struct {
+	kind     tabKind
+	category string // Category of a category tab
+}
+
+// tabZone is the range of columns a visible tab takes in the tab bar
+type tabZone struct {
+	index      int // Index of the category
+	start, end int // Columns from start up to end
+}
+
+// tabBar is the rendered strip of category tabs
+type tabBar struct {
+	view   string
+	zones  []tabZone
+	offset int // First tab shown
+}
+
+// tabLabel names a tab with its number of bookmarks
+func tabLabel(category string, count int) string {
+	return fmt.Sprintf("%s (%d)", category, count)
+}
+
+// layoutTabs picks the tabs shown in a bar of the given width: from offset on,
+// moved just enough to show the active tab and back when there is room. It
+// returns the first and last tab shown.
+func layoutTabs(widths []int, active, offset, width int) (first, last int) {
+	n := len(widths)
+	if n == 0 {
+		return 0, -1
+	}
+
+	// fits reports whether the tabs from i to j fit with their markers
+	fits := func(i, j int) bool {
+		total := 0
+		if i > 0 {
+			total += lipgloss.Width(tabsBefore)
+		}
+		if j < n-1 {
+			total += lipgloss.Width(tabsAfter)
+		}
+		for k := i; k <= j; k++ {
+			total += widths[k]
+		}
+		return total <= width
+	}
+
+	first = min(max(offset, 0), n-1)
+	if active < first {
+		first = active
+	}
+	for first < active && !fits(first, active) {
+		first++
+	}
+	// Show earlier tabs again when the later ones leave room, after a resize
+	// or when the categories change
+	for first > 0 && fits(first-1, n-1) {
+		first--
+	}
+
+	last = first
+	for last+1 < n && fits(first, last+1) {
+		last++
+	}
+	return first, last
+}
+
+// renderTabs draws the tabs with the active one highlighted, scrolled from
+// offset to fit the width
+func renderTabs(labels []string, active, offset, width int) tabBar {
+	rendered := make([]string, len(labels))
+	widths := make([]int, len(labels))
+	for i, label := range labels {
+		style := styles.Tab
+		if i == active {
+			style = styles.ActiveTab
+		}
+		rendered[i] = style.Render(label)
+		widths[i] = lipgloss.Width(rendered[i])
+	}
+
+	first, last := layoutTabs(widths, active, offset, width)
+	bar := tabBar{offset: first}
+
+	var b strings.Builder
+	column := 0
+	if first > 0 {
+		b.WriteString(styles.Hint.Render(tabsBefore))
+		column += lipgloss.Width(tabsBefore)
+	}
+	for i := first; i <= last; i++ {
+		b.WriteString(rendered[i])
+		bar.zones = append(bar.zones, tabZone{index: i, start: column, end: column + widths[i]})
+		column += widths[i]
+	}
+	if last < len(labels)-1 {
+		b.WriteString(styles.Hint.Render(tabsAfter))
+	}
+
+	bar.view = b.String()
+	return bar
+}
+
+// tabAt returns the index of the category whose tab is at a column, or -1
+func (bar tabBar) tabAt(column int) int {
+	for _, z := range bar.zones {
+		if column >= z.start && column < z.end {
+			return z.index
+		}
+	}
+	return -1
+}
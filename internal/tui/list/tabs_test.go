@@ -0,0 +1,81 @@
+package list
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestLayoutTabs(t *testing.T) {
+	// Markers take two columns each
+	widths := []int{10, 10, 10, 10, 10}
+
+	tests := []struct {
+		name      string
+		active    int
+		offset    int
+		width     int
+		wantFirst int
+		wantLast  int
+	}{
+		{"all fit", 4, 0, 50, 0, 4},
+		{"all fit from an offset", 2, 3, 50, 0, 4},
+		{"scrolled right to the active tab", 4, 0, 24, 3, 4},
+		{"kept while the active tab is shown", 3, 2, 24, 2, 3},
+		{"scrolled left to the active tab", 0, 3, 24, 0, 1},
+		{"middle", 2, 2, 20, 2, 2},
+		{"pulled back when there is room", 4, 4, 34, 2, 4},
+		{"too narrow for one tab", 2, 0, 5, 2, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, last := layoutTabs(widths, tt.active, tt.offset, tt.width)
+			if first != tt.wantFirst || last != tt.wantLast {
+				t.Errorf("layoutTabs() = %d, %d; want %d, %d", first, last, tt.wantFirst, tt.wantLast)
+			}
+		})
+	}
+
+	if first, last := layoutTabs(nil, 0, 0, 80); first != 0 || last != -1 {
+		t.Errorf("layoutTabs(nil) = %d, %d; want no tabs", first, last)
+	}
+}
+
+func TestRenderTabs(t *testing.T) {
+	labels := []string{tabLabel("All", 12), tabLabel("work", 3), tabLabel("Trash", 1)}
+
+	bar := renderTabs(labels, 0, 0, 80)
+	if bar.offset != 0 || len(bar.zones) != 3 {
+		t.Fatalf("renderTabs() shows %d tabs from %d, want all 3", len(bar.zones), bar.offset)
+	}
+	if !strings.Contains(bar.view, "work (3)") {
+		t.Errorf("view = %q, want the labels with counts", bar.view)
+	}
+
+	// Columns map to the tabs drawn there
+	zone := bar.zones[1]
+	if got := bar.tabAt(zone.start); got != 1 {
+		t.Errorf("tabAt(%d) = %d, want 1", zone.start, got)
+	}
+	if got := bar.tabAt(zone.end); got != 2 {
+		t.Errorf("tabAt(%d) = %d, want 2", zone.end, got)
+	}
+	if got := bar.tabAt(lipgloss.Width(bar.view)); got != -1 {
+		t.Errorf("tabAt() past the tabs = %d, want -1", got)
+	}
+
+	// Scrolled, the marker comes first and the zones follow it; the last tab
+	// takes 11 columns and its marker 2
+	bar = renderTabs(labels, 2, 0, 15)
+	if bar.offset != 2 || !strings.HasPrefix(bar.view, tabsBefore) {
+		t.Fatalf("renderTabs() = %q from %d, want the last tab after a marker", bar.view, bar.offset)
+	}
+	if got := bar.tabAt(0); got != -1 {
+		t.Errorf("tabAt(0) = %d, want the marker to switch nothing", got)
+	}
+	if got := bar.tabAt(lipgloss.Width(tabsBefore) - 1); got != -1 {
+		t.Errorf("tabAt() on the marker = %d, want -1", got)
+	}
+}
@@ -0,0 +1,171 @@
+// Package status provides the notification area shown below the TUI list:
+// transient toasts for successful operations and a persistent error bar.
+package status
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jhoffmann/bookmark-manager/internal/tui/styles"
+)
+
+// DefaultToastDuration is how long toasts stay visible
+const DefaultToastDuration = 3 * time.Second
+
+// Level is the severity of a notification
+type Level int
+
+const (
+	// Info is a neutral notification, e.g. "Nothing to undo"
+	Info Level = iota
+	// Success confirms a completed operation
+	Success
+	// Error reports a failed operation and stays until dismissed
+	Error
+)
+
+// Model holds the notification currently shown
+type Model struct {
+	level    Level
+	message  string
+	detail   string
+	seq      int // Identifies the current toast so stale expiries are ignored
+	duration time.Duration
+	dismiss  string // Keys dismissing the error bar, as shown in its hint
+}
+
+// New creates an empty notification area
+func New() Model {
+	return Model{duration: DefaultToastDuration, dismiss: "esc"}
+}
+
+// SetDismissKey sets the binding dismissing the error bar, whose keys its hint
+// shows; without keys there is no hint
+func (m *Model) SetDismissKey(dismiss key.Binding) {
+	m.dismiss = ""
+	if dismiss.Enabled() {
+		m.dismiss = dismiss.Help().Key
+	}
+}
+
+// expiredMsg is sent when a toast's display time is over
+type expiredMsg struct {
+	seq int
+}
+
+// Info shows a neutral toast and returns the command that expires it
+func (m *Model) Info(message string) tea.Cmd {
+	return m.toast(Info, message)
+}
+
+// Success shows a success toast and returns the command that expires it
+func (m *Model) Success(message string) tea.Cmd {
+	return m.toast(Success, message)
+}
+
+// Error shows a persistent error bar with the error as details. It replaces
+// any toast and stays visible until Dismiss is called.
+func (m *Model) Error(message string, err error) {
+	m.seq++
+	m.level = Error
+	m.message = message
+	m.detail = ""
+	if err != nil {
+		m.detail = err.Error()
+	}
+}
+
+// Dismiss clears the current notification
+func (m *Model) Dismiss() {
+	m.seq++
+	m.message = ""
+	m.detail = ""
+}
+
+// HasError reports whether the error bar is shown
+func (m Model) HasError() bool {
+	return m.message != "" && m.level == Error
+}
+
+// Visible reports whether any notification is shown
+func (m Model) Visible() bool {
+	return m.message != ""
+}
+
+// Update expires toasts whose display time is over
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	if msg, ok := msg.(expiredMsg); ok && msg.seq == m.seq && m.level != Error {
+		m.message = ""
+	}
+	return m, nil
+}
+
+// Height returns the number of lines View renders
+func (m Model) Height() int {
+	switch {
+	case !m.Visible():
+		return 0
+	case m.level == Error && m.detail != "":
+		return 2
+	default:
+		return 1
+	}
+}
+
+// View renders the notification truncated to the given width. The output
+// starts with a newline so it can be appended directly below other content.
+func (m Model) View(width int) string {
+	if !m.Visible() {
+		return ""
+	}
+
+	var lines []string
+	switch m.level {
+	case Success:
+		lines = append(lines, styles.SuccessMessage.Render("✓ "+truncate(m.message, width-4)))
+	case Error:
+		hint := ""
+		if m.dismiss != "" {
+			hint = "(" + m.dismiss + " to dismiss)"
+		}
+		lines = append(lines, styles.ErrorMessage.Render("✗ "+truncate(m.message, width-4-lipgloss.Width(hint)))+
+			styles.Hint.Render(hint))
+		if m.detail != "" {
+			lines = append(lines, lipgloss.NewStyle().Foreground(styles.Error).Padding(0, 3).
+				Render(truncate(m.detail, width-6)))
+		}
+	default:
+		lines = append(lines, styles.WarningMessage.Render(truncate(m.message, width-2)))
+	}
+
+	return "\n" + strings.Join(lines, "\n")
+}
+
+func (m *Model) toast(level Level, message string) tea.Cmd {
+	m.seq++
+	m.level = level
+	m.message = message
+	m.detail = ""
+
+	seq := m.seq
+	return tea.Tick(m.duration, func(time.Time) tea.Msg {
+		return expiredMsg{seq: seq}
+	})
+}
+
+// truncate shortens s to at most width cells, marking the cut with an ellipsis
+func truncate(s string, width int) string {
+	// Details may span lines (e.g. wrapped command output); keep them on one
+	s = strings.Join(strings.Fields(s), " ")
+	if width <= 0 || lipgloss.Width(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && lipgloss.Width(string(runes))+1 > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
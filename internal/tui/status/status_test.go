@@ -0,0 +1,87 @@
+package status
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+func TestToastExpires(t *testing.T) {
+	m := New()
+
+	if cmd := m.Success("Saved"); cmd == nil {
+		t.Fatal("Success() should return a command expiring the toast")
+	}
+	if !m.Visible() || m.HasError() {
+		t.Fatal("Expected a visible success toast")
+	}
+
+	// An expiry for an older toast must not hide the current one
+	m.Info("Nothing to undo")
+	m, _ = m.Update(expiredMsg{seq: m.seq - 1})
+	if !m.Visible() {
+		t.Error("Stale expiry hid the current toast")
+	}
+
+	m, _ = m.Update(expiredMsg{seq: m.seq})
+	if m.Visible() {
+		t.Error("Expected toast to be hidden after it expired")
+	}
+}
+
+func TestErrorPersistsUntilDismissed(t *testing.T) {
+	m := New()
+	m.Success("Saved")
+	seq := m.seq
+
+	m.Error("Failed to open /tmp/x", errors.New("exec: \"xdg-open\": executable file not found"))
+	if !m.HasError() {
+		t.Fatal("Expected the error bar to be shown")
+	}
+
+	// The pending expiry of the replaced toast must not clear the error
+	m, _ = m.Update(expiredMsg{seq: seq})
+	m, _ = m.Update(expiredMsg{seq: m.seq})
+	if !m.HasError() {
+		t.Fatal("Error bar should stay until dismissed")
+	}
+
+	view := m.View(80)
+	if !strings.Contains(view, "Failed to open /tmp/x") || !strings.Contains(view, "xdg-open") {
+		t.Errorf("View() = %q, want message and details", view)
+	}
+	if m.Height() != 2 {
+		t.Errorf("Height() = %d, want 2", m.Height())
+	}
+
+	if !strings.Contains(view, "(esc to dismiss)") {
+		t.Errorf("View() = %q, want the default dismiss key", view)
+	}
+	m.SetDismissKey(key.NewBinding(key.WithKeys("ctrl+g"), key.WithHelp("ctrl+g", "dismiss error")))
+	if view := m.View(80); !strings.Contains(view, "(ctrl+g to dismiss)") {
+		t.Errorf("View() = %q, want the bound dismiss key", view)
+	}
+	m.SetDismissKey(key.NewBinding(key.WithDisabled()))
+	if view := m.View(80); strings.Contains(view, "to dismiss") {
+		t.Errorf("View() = %q, want no hint without a dismiss key", view)
+	}
+
+	m.Dismiss()
+	if m.Visible() || m.View(80) != "" {
+		t.Error("Expected nothing to be shown after Dismiss()")
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate("short", 10); got != "short" {
+		t.Errorf("truncate() = %q, want unchanged", got)
+	}
+	if got := truncate("multi\nline  detail", 40); got != "multi line detail" {
+		t.Errorf("truncate() = %q, want whitespace collapsed", got)
+	}
+	if got := truncate("a very long message", 8); got != "a very …" {
+		t.Errorf("truncate() = %q, want %q", got, "a very …")
+	}
+}
@@ -1,50 +1,243 @@
-// Package styles provides consistent styling for the TUI using lipgloss.
+// Package styles provides consistent styling for the TUI and the command line
+// messages using lipgloss. The styles are built from the active theme, see
+// Apply.
 package styles
 
 import (
+	"os"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 )
 
-// Color palette for the bookmark manager TUI
+// Color palette of the active theme
 var (
-	Primary = lipgloss.Color("#e49fdb") // Magenta
-	Success = lipgloss.Color("#7ac68f") // Green
-	Warning = lipgloss.Color("#cdb36b") // Yellow
-	Error   = lipgloss.Color("#fc9c93") // Red
-	Muted   = lipgloss.Color("#404040") // Gray
+	Primary   lipgloss.TerminalColor // Selection, focused input and filter matches
+	Secondary lipgloss.TerminalColor // Selection border and description
+	Text      lipgloss.TerminalColor // Item titles
+	Subtle    lipgloss.TerminalColor // Descriptions, status bar and help keys
+	Success   lipgloss.TerminalColor
+	Warning   lipgloss.TerminalColor
+	Error     lipgloss.TerminalColor
+	Muted     lipgloss.TerminalColor // Borders, hints and help descriptions
 )
 
 // Input styles
 var (
 	// FilterInput style for the search filter
-	FilterInput = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(Muted).
-			Padding(0, 1).
-			MarginBottom(1)
+	FilterInput lipgloss.Style
 
 	// FocusedFilterInput style when filter is focused
-	FocusedFilterInput = FilterInput.Copy().
-				BorderForeground(Primary)
+	FocusedFilterInput lipgloss.Style
+
+	// FilterMatch style for characters matched by the filter
+	FilterMatch lipgloss.Style
+)
+
+// Text styles
+var (
+	// Title style for the titles of the list and the dialogs
+	Title lipgloss.Style
+
+	// Hint style for secondary text such as key hints
+	Hint lipgloss.Style
+)
+
+// Tab styles
+var (
+	// Tab style for the category tabs
+	Tab lipgloss.Style
+
+	// ActiveTab style for the tab of the category shown
+	ActiveTab lipgloss.Style
 )
 
 // Message styles
 var (
 	// SuccessMessage style for success notifications
-	SuccessMessage = lipgloss.NewStyle().
-			Foreground(Success).
-			Bold(true).
-			Padding(0, 1)
+	SuccessMessage lipgloss.Style
 
 	// ErrorMessage style for error notifications
-	ErrorMessage = lipgloss.NewStyle().
-			Foreground(Error).
-			Bold(true).
-			Padding(0, 1)
+	ErrorMessage lipgloss.Style
 
 	// WarningMessage style for warning notifications
-	WarningMessage = lipgloss.NewStyle().
-			Foreground(Warning).
-			Bold(true).
-			Padding(0, 1)
+	WarningMessage lipgloss.Style
 )
+
+// active is the theme the styles are built from
+var active Theme
+
+// noColor is set by DisableColor
+var noColor bool
+
+func init() {
+	Apply(builtins[DefaultTheme])
+}
+
+// Apply builds the styles from a theme. Components created afterwards use
+// them.
+func Apply(theme Theme) {
+	active = theme
+
+	Primary = theme.Primary
+	Secondary = theme.Secondary
+	Text = theme.Text
+	Subtle = theme.Subtle
+	Success = theme.Success
+	Warning = theme.Warning
+	Error = theme.Error
+	Muted = theme.Muted
+
+	FilterInput = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(Muted).
+		Padding(0, 1).
+		MarginBottom(1)
+	FocusedFilterInput = FilterInput.
+		BorderForeground(Primary)
+	if theme.Monochrome || NoColor() {
+		// Without colors the border shows the focus
+		FocusedFilterInput = FocusedFilterInput.Border(lipgloss.ThickBorder())
+	}
+
+	FilterMatch = lipgloss.NewStyle().
+		Foreground(Primary).
+		Bold(true).
+		Underline(true)
+
+	Title = lipgloss.NewStyle().
+		Foreground(theme.Title).
+		Background(theme.TitleBackground).
+		Padding(0, 1)
+	if theme.Monochrome {
+		Title = Title.Reverse(true).Bold(true)
+	}
+
+	Hint = lipgloss.NewStyle().Foreground(Muted)
+	if theme.Monochrome {
+		Hint = Hint.Faint(true)
+	}
+
+	Tab = lipgloss.NewStyle().
+		Foreground(Subtle).
+		Padding(0, 1)
+	ActiveTab = Tab.
+		Foreground(theme.Title).
+		Background(theme.TitleBackground).
+		Bold(true)
+	if theme.Monochrome || NoColor() {
+		// Without colors the active tab is reversed
+		ActiveTab = ActiveTab.Reverse(true)
+	}
+
+	SuccessMessage = message(Success)
+	ErrorMessage = message(Error)
+	WarningMessage = message(Warning)
+}
+
+// message creates the style of a notification
+func message(color lipgloss.TerminalColor) lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(color).
+		Bold(true).
+		Padding(0, 1)
+}
+
+// Active returns the theme the styles are built from
+func Active() Theme {
+	return active
+}
+
+// NoColor reports whether colors are disabled, with NO_COLOR or DisableColor
+func NoColor() bool {
+	return noColor || os.Getenv("NO_COLOR") != ""
+}
+
+// DisableColor turns colors off, as NO_COLOR does
+func DisableColor() {
+	noColor = true
+	lipgloss.SetColorProfile(termenv.Ascii)
+	Apply(active)
+}
+
+// SetBackground sets the terminal background the theme colors are chosen
+// for: light, dark, or auto (or empty) to ask the terminal
+func SetBackground(background string) {
+	switch background {
+	case "light":
+		lipgloss.SetHasDarkBackground(false)
+	case "dark":
+		lipgloss.SetHasDarkBackground(true)
+	}
+}
+
+// ItemStyles returns the styles of list items
+func ItemStyles() list.DefaultItemStyles {
+	s := list.NewDefaultItemStyles()
+	s.NormalTitle = s.NormalTitle.Foreground(Text)
+	s.NormalDesc = s.NormalDesc.Foreground(Subtle)
+	s.SelectedTitle = s.SelectedTitle.Foreground(Primary).BorderForeground(Secondary)
+	s.SelectedDesc = s.SelectedDesc.Foreground(Secondary).BorderForeground(Secondary)
+	s.DimmedTitle = s.DimmedTitle.Foreground(Subtle)
+	s.DimmedDesc = s.DimmedDesc.Foreground(Muted)
+	s.FilterMatch = FilterMatch
+	if active.Monochrome {
+		s.SelectedTitle = s.SelectedTitle.Bold(true)
+		s.DimmedTitle = s.DimmedTitle.Faint(true)
+		s.DimmedDesc = s.DimmedDesc.Faint(true)
+	}
+	return s
+}
+
+// StyleList applies the theme to a list's title, status bar, pagination and
+// help
+func StyleList(l *list.Model) {
+	s := list.DefaultStyles()
+	s.Title = Title
+	s.Spinner = s.Spinner.Foreground(Subtle)
+	s.FilterPrompt = s.FilterPrompt.Foreground(Primary)
+	s.FilterCursor = s.FilterCursor.Foreground(Primary)
+	s.DefaultFilterCharacterMatch = FilterMatch
+	s.StatusBar = s.StatusBar.Foreground(Subtle)
+	s.StatusEmpty = s.StatusEmpty.Foreground(Subtle)
+	s.StatusBarActiveFilter = s.StatusBarActiveFilter.Foreground(Text)
+	s.StatusBarFilterCount = s.StatusBarFilterCount.Foreground(Muted)
+	s.NoItems = s.NoItems.Foreground(Subtle)
+	s.ActivePaginationDot = s.ActivePaginationDot.Foreground(Subtle)
+	s.InactivePaginationDot = s.InactivePaginationDot.Foreground(Muted)
+	s.DividerDot = s.DividerDot.Foreground(Muted)
+	l.Styles = s
+
+	l.Help.Styles = HelpStyles()
+}
+
+// HelpStyles returns the styles of the key help
+func HelpStyles() help.Styles {
+	keyStyle := lipgloss.NewStyle().Foreground(Subtle)
+	descStyle := lipgloss.NewStyle().Foreground(Muted)
+	sepStyle := lipgloss.NewStyle().Foreground(Muted)
+	if active.Monochrome {
+		keyStyle = keyStyle.Bold(true)
+		descStyle = descStyle.Faint(true)
+	}
+	return help.Styles{
+		Ellipsis:       sepStyle,
+		ShortKey:       keyStyle,
+		ShortDesc:      descStyle,
+		ShortSeparator: sepStyle,
+		FullKey:        keyStyle,
+		FullDesc:       descStyle,
+		FullSeparator:  sepStyle,
+	}
+}
+
+// StyleInput applies the theme to a text input
+func StyleInput(ti *textinput.Model) {
+	ti.PromptStyle = lipgloss.NewStyle().Foreground(Primary)
+	ti.TextStyle = lipgloss.NewStyle().Foreground(Text)
+	ti.PlaceholderStyle = lipgloss.NewStyle().Foreground(Muted)
+	ti.Cursor.Style = lipgloss.NewStyle().Foreground(Primary)
+}
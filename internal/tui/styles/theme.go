@@ -0,0 +1,191 @@
+package styles
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jhoffmann/bookmark-manager/internal/config"
+)
+
+// Theme is a named set of colors the styles are built from
+type Theme struct {
+	Name            string
+	Primary         lipgloss.TerminalColor
+	Secondary       lipgloss.TerminalColor
+	Text            lipgloss.TerminalColor
+	Subtle          lipgloss.TerminalColor
+	Muted           lipgloss.TerminalColor
+	Title           lipgloss.TerminalColor
+	TitleBackground lipgloss.TerminalColor
+	Success         lipgloss.TerminalColor
+	Warning         lipgloss.TerminalColor
+	Error           lipgloss.TerminalColor
+	// Monochrome themes have no colors and show emphasis with bold, faint
+	// and reverse text instead
+	Monochrome bool
+}
+
+// DefaultTheme is the theme used when none is configured
+const DefaultTheme = "default"
+
+// adaptive returns a color depending on the terminal background
+func adaptive(light, dark string) lipgloss.TerminalColor {
+	return lipgloss.AdaptiveColor{Light: light, Dark: dark}
+}
+
+// builtins are the built-in themes by name. Message colors do not depend on
+// the background so that printing them never queries the terminal.
+var builtins = map[string]Theme{
+	DefaultTheme: {
+		Name:            DefaultTheme,
+		Primary:         adaptive("#c04fb2", "#e49fdb"),
+		Secondary:       adaptive("#f793ff", "#ad58b4"),
+		Text:            adaptive("#1a1a1a", "#dddddd"),
+		Subtle:          adaptive("#a49fa5", "#777777"),
+		Muted:           adaptive("#c2b8c2", "#404040"),
+		Title:           lipgloss.Color("#fffdf5"),
+		TitleBackground: lipgloss.Color("#5a56e0"),
+		Success:         lipgloss.Color("#7ac68f"),
+		Warning:         lipgloss.Color("#cdb36b"),
+		Error:           lipgloss.Color("#fc9c93"),
+	},
+	// ansi uses the terminal's own palette
+	"ansi": {
+		Name:            "ansi",
+		Primary:         lipgloss.Color("5"),
+		Secondary:       lipgloss.Color("13"),
+		Text:            lipgloss.NoColor{},
+		Subtle:          lipgloss.Color("8"),
+		Muted:           lipgloss.Color("8"),
+		Title:           lipgloss.Color("0"),
+		TitleBackground: lipgloss.Color("4"),
+		Success:         lipgloss.Color("2"),
+		Warning:         lipgloss.Color("3"),
+		Error:           lipgloss.Color("1"),
+	},
+	"dracula": {
+		Name:            "dracula",
+		Primary:         lipgloss.Color("#ff79c6"),
+		Secondary:       lipgloss.Color("#bd93f9"),
+		Text:            lipgloss.Color("#f8f8f2"),
+		Subtle:          lipgloss.Color("#6272a4"),
+		Muted:           lipgloss.Color("#44475a"),
+		Title:           lipgloss.Color("#282a36"),
+		TitleBackground: lipgloss.Color("#bd93f9"),
+		Success:         lipgloss.Color("#50fa7b"),
+		Warning:         lipgloss.Color("#f1fa8c"),
+		Error:           lipgloss.Color("#ff5555"),
+	},
+	"solarized": {
+		Name:            "solarized",
+		Primary:         lipgloss.Color("#268bd2"),
+		Secondary:       lipgloss.Color("#2aa198"),
+		Text:            adaptive("#586e75", "#93a1a1"),
+		Subtle:          adaptive("#93a1a1", "#586e75"),
+		Muted:           adaptive("#eee8d5", "#073642"),
+		Title:           adaptive("#fdf6e3", "#002b36"),
+		TitleBackground: lipgloss.Color("#268bd2"),
+		Success:         lipgloss.Color("#859900"),
+		Warning:         lipgloss.Color("#b58900"),
+		Error:           lipgloss.Color("#dc322f"),
+	},
+	"mono": {
+		Name:            "mono",
+		Primary:         lipgloss.NoColor{},
+		Secondary:       lipgloss.NoColor{},
+		Text:            lipgloss.NoColor{},
+		Subtle:          lipgloss.NoColor{},
+		Muted:           lipgloss.NoColor{},
+		Title:           lipgloss.NoColor{},
+		TitleBackground: lipgloss.NoColor{},
+		Success:         lipgloss.NoColor{},
+		Warning:         lipgloss.NoColor{},
+		Error:           lipgloss.NoColor{},
+		Monochrome:      true,
+	},
+}
+
+// Themes returns the names of the built-in themes, sorted
+func Themes() []string {
+	names := make([]string, 0, len(builtins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Load returns the theme of the given name, a built-in theme or one of the
+// user themes, which replace colors of their base theme. An empty name is the
+// default theme.
+func Load(name string, themes map[string]config.Theme) (Theme, error) {
+	if name == "" {
+		name = DefaultTheme
+	}
+	return resolve(name, themes, nil)
+}
+
+// resolve builds a theme, following the bases of user themes; seen holds the
+// user themes being resolved to detect cycles
+func resolve(name string, themes map[string]config.Theme, seen []string) (Theme, error) {
+	user, isUser := themes[name]
+	builtin, isBuiltin := builtins[name]
+	switch {
+	case isUser && isBuiltin:
+		return Theme{}, fmt.Errorf("%s%s: a built-in theme has this name", config.ThemesPrefix, name)
+	case isBuiltin:
+		return builtin, nil
+	case !isUser:
+		known := Themes()
+		for user := range themes {
+			known = append(known, user)
+		}
+		sort.Strings(known)
+		return Theme{}, fmt.Errorf("unknown theme %q (expected one of %s)", name, strings.Join(known, ", "))
+	}
+
+	for _, s := range seen {
+		if s == name {
+			return Theme{}, fmt.Errorf("%s%s: base themes form a cycle: %s -> %s",
+				config.ThemesPrefix, seen[0], strings.Join(seen, " -> "), name)
+		}
+	}
+
+	base := user.Base
+	if base == "" {
+		base = DefaultTheme
+	}
+	theme, err := resolve(base, themes, append(seen, name))
+	if err != nil {
+		return Theme{}, err
+	}
+
+	theme.Name = name
+	set := func(field *lipgloss.TerminalColor, c config.Color) {
+		if !c.IsZero() {
+			*field = color(c)
+		}
+	}
+	set(&theme.Primary, user.Primary)
+	set(&theme.Secondary, user.Secondary)
+	set(&theme.Text, user.Text)
+	set(&theme.Subtle, user.Subtle)
+	set(&theme.Muted, user.Muted)
+	set(&theme.Title, user.Title)
+	set(&theme.TitleBackground, user.TitleBackground)
+	set(&theme.Success, user.Success)
+	set(&theme.Warning, user.Warning)
+	set(&theme.Error, user.Error)
+	return theme, nil
+}
+
+// color converts a configured color, which only depends on the background
+// when its light and dark variants differ
+func color(c config.Color) lipgloss.TerminalColor {
+	if c.Light == c.Dark {
+		return lipgloss.Color(c.Dark)
+	}
+	return adaptive(c.Light, c.Dark)
+}
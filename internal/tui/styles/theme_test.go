@@ -0,0 +1,120 @@
+package styles
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jhoffmann/bookmark-manager/internal/config"
+)
+
+func TestLoad_Builtin(t *testing.T) {
+	for _, name := range Themes() {
+		theme, err := Load(name, nil)
+		if err != nil {
+			t.Fatalf("Load(%q) error = %v", name, err)
+		}
+		if theme.Name != name {
+			t.Errorf("Load(%q).Name = %q", name, theme.Name)
+		}
+		// Every color is set, NoColor included
+		for _, c := range []lipgloss.TerminalColor{theme.Primary, theme.Secondary, theme.Text, theme.Subtle,
+			theme.Muted, theme.Title, theme.TitleBackground, theme.Success, theme.Warning, theme.Error} {
+			if c == nil {
+				t.Errorf("theme %q has an unset color", name)
+			}
+		}
+	}
+
+	theme, err := Load("", nil)
+	if err != nil || theme.Name != DefaultTheme {
+		t.Errorf("Load(\"\") = %q, %v; want the default theme", theme.Name, err)
+	}
+}
+
+func TestLoad_UserThemes(t *testing.T) {
+	themes := map[string]config.Theme{
+		"base": {
+			Base:    "dracula",
+			Primary: config.Color{Light: "#111111", Dark: "#eeeeee"},
+		},
+		"mine": {
+			Base:  "base",
+			Error: config.Color{Light: "9", Dark: "9"},
+		},
+		"plain": {
+			Success: config.Color{Light: "#00ff00", Dark: "#00ff00"},
+		},
+	}
+
+	theme, err := Load("mine", themes)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if theme.Name != "mine" {
+		t.Errorf("Name = %q, want mine", theme.Name)
+	}
+	if theme.Error != lipgloss.Color("9") {
+		t.Errorf("Error = %v, want 9 from the theme", theme.Error)
+	}
+	want := lipgloss.AdaptiveColor{Light: "#111111", Dark: "#eeeeee"}
+	if theme.Primary != want {
+		t.Errorf("Primary = %v, want %v from the base theme", theme.Primary, want)
+	}
+	if theme.Secondary != builtins["dracula"].Secondary {
+		t.Errorf("Secondary = %v, want the dracula color", theme.Secondary)
+	}
+
+	// Without a base, colors come from the default theme
+	theme, err = Load("plain", themes)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if theme.Primary != builtins[DefaultTheme].Primary {
+		t.Errorf("Primary = %v, want the default color", theme.Primary)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		theme   string
+		themes  map[string]config.Theme
+		wantErr string
+	}{
+		{"unknown", "nope", nil, `unknown theme "nope"`},
+		{"unknown base", "mine", map[string]config.Theme{"mine": {Base: "nope"}}, `unknown theme "nope"`},
+		{"cycle", "a", map[string]config.Theme{"a": {Base: "b"}, "b": {Base: "a"}}, "a -> b -> a"},
+		{"self", "a", map[string]config.Theme{"a": {Base: "a"}}, "a -> a"},
+		{"built-in name", "dracula", map[string]config.Theme{"dracula": {}}, "a built-in theme has this name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(tt.theme, tt.themes)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	defer Apply(builtins[DefaultTheme])
+
+	Apply(builtins["dracula"])
+	if Active().Name != "dracula" {
+		t.Errorf("Active() = %q, want dracula", Active().Name)
+	}
+	if ErrorMessage.GetForeground() != builtins["dracula"].Error {
+		t.Errorf("ErrorMessage foreground = %v, want the dracula error color", ErrorMessage.GetForeground())
+	}
+	if ItemStyles().SelectedTitle.GetForeground() != builtins["dracula"].Primary {
+		t.Error("Expected selected items to use the primary color")
+	}
+
+	Apply(builtins["mono"])
+	if !Title.GetReverse() {
+		t.Error("Expected monochrome titles to be reversed")
+	}
+}
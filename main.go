@@ -12,7 +12,21 @@ func main() {
 	// Get subcommands
 	addCmd := cmd.GetAddCmd()
 	listCmd := cmd.GetListCmd()
+	lsCmd := cmd.GetLsCmd()
+	pickCmd := cmd.GetPickCmd()
 	exportCmd := cmd.GetExportCmd()
+	importCmd := cmd.GetImportCmd()
+	jumpCmd := cmd.GetJumpCmd()
+	dbCmd := cmd.GetDBCmd()
+	trashCmd := cmd.GetTrashCmd()
+	doctorCmd := cmd.GetDoctorCmd()
+	setCategoryCmd := cmd.GetSetCategoryCmd()
+	categoryCmd := cmd.GetCategoryCmd()
+	rmCmd := cmd.GetRmCmd()
+	resolveCmd := cmd.GetResolveCmd()
+	visitCmd := cmd.GetVisitCmd()
+	initCmd := cmd.GetInitCmd()
+	configCmd := cmd.GetConfigCmd()
 
 	rootCmd := &cobra.Command{
 		Use:   "bookmark-manager",
@@ -26,7 +40,24 @@ func main() {
 	// Add subcommands
 	rootCmd.AddCommand(addCmd)
 	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(lsCmd)
+	rootCmd.AddCommand(pickCmd)
 	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(jumpCmd)
+	rootCmd.AddCommand(dbCmd)
+	rootCmd.AddCommand(trashCmd)
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(setCategoryCmd)
+	rootCmd.AddCommand(categoryCmd)
+	rootCmd.AddCommand(rmCmd)
+	rootCmd.AddCommand(resolveCmd)
+	rootCmd.AddCommand(visitCmd)
+	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(configCmd)
+
+	// Global flags selecting the config file and overriding settings
+	cmd.AddConfigFlags(rootCmd)
 
 	// Execute root command
 	if err := rootCmd.Execute(); err != nil {